@@ -8,15 +8,31 @@ import (
 	"github.com/ONSdigital/dp-florence-api/data"
 	"github.com/ONSdigital/dp-florence-api/data/model"
 	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
 )
 
+// SessionTimeout is how long a token remains valid after it was last used
+const SessionTimeout = time.Minute * 60
+
 type ctxKey int
 
 const (
 	token ctxKey = iota
 	user
+	permissions
+	collection
 )
 
+// PermissionSet is the effective set of permissions for a user, keyed by
+// permission name, with every grant of that permission from the user's roles
+type PermissionSet map[string][]model.Permission
+
+// Has returns true if the set contains perm
+func (p PermissionSet) Has(perm string) bool {
+	_, ok := p[perm]
+	return ok
+}
+
 // Middleware is the auth middleware
 func Middleware(db *data.MongoDB, requireValid bool) func(h http.HandlerFunc) http.Handler {
 	return func(h http.HandlerFunc) http.Handler {
@@ -33,7 +49,7 @@ func Middleware(db *data.MongoDB, requireValid bool) func(h http.HandlerFunc) ht
 					return
 				}
 
-				if tok.LastActive.Add(time.Minute * 60).Before(time.Now()) {
+				if tok.LastActive.Add(SessionTimeout).Before(time.Now()) {
 					log.DebugR(req, "token expired", nil)
 					w.WriteHeader(401)
 					return
@@ -48,7 +64,15 @@ func Middleware(db *data.MongoDB, requireValid bool) func(h http.HandlerFunc) ht
 			}
 
 			log.DebugR(req, "user loaded", log.Data{"user": u})
-			h.ServeHTTP(w, req.WithContext(withContext(req, t, &u)))
+
+			perms, err := ResolvePermissions(db, u)
+			if err != nil {
+				log.ErrorR(req, err, nil)
+				w.WriteHeader(500)
+				return
+			}
+
+			h.ServeHTTP(w, req.WithContext(withContext(req, t, &u, perms)))
 		})
 	}
 }
@@ -65,7 +89,7 @@ func WithPermission(db *data.MongoDB, perm string) func(h http.HandlerFunc) http
 			}
 
 			if !ok {
-				log.DebugR(req, "user needs administrator permission", nil)
+				log.DebugR(req, "user needs permission", log.Data{"permission": perm})
 				w.WriteHeader(403)
 				return
 			}
@@ -75,8 +99,10 @@ func WithPermission(db *data.MongoDB, perm string) func(h http.HandlerFunc) http
 	}
 }
 
-func withContext(req *http.Request, t string, u *model.User) context.Context {
-	return context.WithValue(context.WithValue(req.Context(), token, t), user, u)
+func withContext(req *http.Request, t string, u *model.User, p PermissionSet) context.Context {
+	ctx := context.WithValue(req.Context(), token, t)
+	ctx = context.WithValue(ctx, user, u)
+	return context.WithValue(ctx, permissions, p)
 }
 
 // UserFromContext ...
@@ -91,20 +117,202 @@ func TokenFromContext(ctx context.Context) (token string, ok bool) {
 	return u, ok
 }
 
+// WithCollection loads the collection named by the collection_id route
+// variable, checks the user can access it and adds it to the request context
+func WithCollection(db *data.MongoDB) func(h http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			id := mux.Vars(req)["collection_id"]
+			if len(id) == 0 {
+				w.WriteHeader(400)
+				return
+			}
+
+			c, err := db.GetCollection(id)
+			if err != nil {
+				log.DebugR(req, "error fetching collection", log.Data{"error": err})
+				if err == data.ErrCollectionNotFound {
+					w.WriteHeader(404)
+					return
+				}
+				w.WriteHeader(500)
+				return
+			}
+
+			ok, err := CanAccessCollection(req.Context(), db, c)
+			if err != nil {
+				log.ErrorR(req, err, nil)
+				w.WriteHeader(500)
+				return
+			}
+
+			if !ok {
+				log.DebugR(req, "user can't access collection", log.Data{"collection_id": id})
+				w.WriteHeader(403)
+				return
+			}
+
+			h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), collection, &c)))
+		}
+	}
+}
+
+// CollectionFromContext ...
+func CollectionFromContext(ctx context.Context) (c *model.Collection, ok bool) {
+	c, ok = ctx.Value(collection).(*model.Collection)
+	return
+}
+
+// PermissionsFromContext ...
+func PermissionsFromContext(ctx context.Context) (p PermissionSet, ok bool) {
+	p, ok = ctx.Value(permissions).(PermissionSet)
+	return
+}
+
+// ResolvePermissions flattens the permissions from each of the user's roles
+func ResolvePermissions(db *data.MongoDB, u model.User) (PermissionSet, error) {
+	p := make(PermissionSet)
+
+	for _, r := range u.EffectiveRoles() {
+		role, err := db.GetRole(r)
+		if err != nil {
+			if err == data.ErrRoleNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		for k, v := range role.Permissions {
+			p[k] = append(p[k], v)
+		}
+	}
+
+	return p, nil
+}
+
 // HasPermission ...
 func HasPermission(ctx context.Context, db *data.MongoDB, perm string) (ok bool, err error) {
+	p, err := permissionsFor(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	return p.Has(perm), nil
+}
+
+// Grants returns every grant of perm held by the user's roles
+func Grants(ctx context.Context, db *data.MongoDB, perm string) ([]model.Permission, error) {
+	p, err := permissionsFor(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return p[perm], nil
+}
+
+func permissionsFor(ctx context.Context, db *data.MongoDB) (PermissionSet, error) {
+	if p, ok := PermissionsFromContext(ctx); ok {
+		return p, nil
+	}
+
+	u, ok := UserFromContext(ctx)
+	if !ok {
+		return PermissionSet{}, nil
+	}
+
+	return ResolvePermissions(db, *u)
+}
+
+// CanCreateCollection returns true if the user may create collections of type t
+func CanCreateCollection(ctx context.Context, db *data.MongoDB, t string) (bool, error) {
+	grants, err := Grants(ctx, db, model.PermCollectionsCreate)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range grants {
+		if p.AllowsCollectionType(t) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CanEditContent returns true if the user may edit content at uri
+func CanEditContent(ctx context.Context, db *data.MongoDB, uri string) (bool, error) {
+	grants, err := Grants(ctx, db, model.PermContentWrite)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range grants {
+		if p.AllowsContentPath(uri) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CanAccessCollection returns true if the user can see the collection, either
+// because they're in one of its teams or they can see all collections. A
+// collection with no teams can only be seen by users who can see all
+// collections.
+func CanAccessCollection(ctx context.Context, db *data.MongoDB, c model.Collection) (bool, error) {
+	ok, err := HasPermission(ctx, db, model.PermCollectionsAll)
+	if err != nil || ok {
+		return ok, err
+	}
+
 	u, ok := UserFromContext(ctx)
 	if !ok {
 		return false, nil
 	}
 
-	for _, r := range u.Roles {
-		role, err := db.GetRole(r)
-		if err != nil {
-			return false, err
+	teams, err := db.GetTeamsForUser(u.Email)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range teams {
+		for _, id := range c.Teams {
+			if t.ID == id {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// CanPublishCollection returns true if the user holds a publish permission
+// which allows the collection's type and every content URI in it
+func CanPublishCollection(ctx context.Context, db *data.MongoDB, c model.Collection) (bool, error) {
+	grants, err := Grants(ctx, db, model.PermCollectionsPublish)
+	if err != nil {
+		return false, err
+	}
+
+	content, err := db.GetCollectionContent(c.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range grants {
+		if !p.AllowsCollectionType(c.Type) {
+			continue
+		}
+
+		ok := true
+		for _, i := range content[c.ID] {
+			if !p.AllowsContentPath(i.URI) {
+				ok = false
+				break
+			}
 		}
 
-		if _, ok := role.Permissions[perm]; ok {
+		if ok {
 			return true, nil
 		}
 	}
@@ -0,0 +1,34 @@
+package content
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// ErrNotFound ...
+var ErrNotFound = errors.New("content not found")
+
+// DataFile is the name of the file holding a page's JSON
+const DataFile = "data.json"
+
+// Reader provides read access to the structure of published content
+type Reader interface {
+	// Exists returns true if there is a page or file at uri
+	Exists(uri string) (bool, error)
+	// Tree returns the page at uri and the pages below it, at most depth
+	// levels deep. A negative depth returns the whole tree.
+	Tree(uri string, depth int) (model.ContentNode, error)
+}
+
+// PageURI returns the URI of the page a content URI belongs to. Files,
+// including a page's data.json, belong to the page of their directory.
+func PageURI(uri string) string {
+	uri = model.CleanURI(uri)
+	if strings.Contains(path.Base(uri), ".") {
+		return path.Dir(uri)
+	}
+	return uri
+}
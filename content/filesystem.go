@@ -0,0 +1,147 @@
+package content
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// FilesystemStore reads published content from a directory on disk.
+//
+// Each page is a directory named after the last element of its URI,
+// containing the page JSON in data.json and any files belonging to the page.
+type FilesystemStore struct {
+	Root string
+}
+
+// NewFilesystemStore ...
+func NewFilesystemStore(root string) *FilesystemStore {
+	return &FilesystemStore{Root: root}
+}
+
+type pageDescription struct {
+	Description struct {
+		Title string `json:"title"`
+	} `json:"description"`
+}
+
+func (s *FilesystemStore) path(uri string) string {
+	return filepath.Join(s.Root, filepath.FromSlash(model.CleanURI(uri)))
+}
+
+// Exists ...
+func (s *FilesystemStore) Exists(uri string) (bool, error) {
+	info, err := os.Stat(s.path(uri))
+	if err == nil && info.IsDir() {
+		_, err = os.Stat(filepath.Join(s.path(uri), DataFile))
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Get ...
+func (s *FilesystemStore) Get(uri string) (*File, error) {
+	p := s.path(uri)
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if info.IsDir() {
+		f.Close()
+		return s.Get(path.Join(model.CleanURI(uri), DataFile))
+	}
+
+	return &File{
+		ReadSeekCloser: f,
+		Name:           info.Name(),
+		Size:           info.Size(),
+		ModTime:        info.ModTime(),
+	}, nil
+}
+
+// Tree ...
+func (s *FilesystemStore) Tree(uri string, depth int) (model.ContentNode, error) {
+	uri = model.CleanURI(uri)
+
+	info, err := os.Stat(s.path(uri))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.ContentNode{}, ErrNotFound
+		}
+		return model.ContentNode{}, err
+	}
+
+	if !info.IsDir() {
+		return model.ContentNode{}, ErrNotFound
+	}
+
+	return s.node(uri, depth)
+}
+
+func (s *FilesystemStore) node(uri string, depth int) (model.ContentNode, error) {
+	n := model.ContentNode{
+		URI:   uri,
+		Title: s.title(uri),
+	}
+
+	entries, err := ioutil.ReadDir(s.path(uri))
+	if err != nil {
+		return model.ContentNode{}, err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		n.HasChildren = true
+		if depth == 0 {
+			break
+		}
+
+		child, err := s.node(path.Join(uri, e.Name()), depth-1)
+		if err != nil {
+			return model.ContentNode{}, err
+		}
+		n.Children = append(n.Children, child)
+	}
+
+	return n, nil
+}
+
+// title reads the page title from data.json, falling back to the last
+// element of the URI
+func (s *FilesystemStore) title(uri string) string {
+	var p pageDescription
+
+	b, err := ioutil.ReadFile(filepath.Join(s.path(uri), DataFile))
+	if err == nil && json.Unmarshal(b, &p) == nil && len(p.Description.Title) > 0 {
+		return p.Description.Title
+	}
+
+	if uri == "/" {
+		return ""
+	}
+	return path.Base(uri)
+}
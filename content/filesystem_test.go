@@ -0,0 +1,150 @@
+package content
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// writeContent creates a content directory holding files keyed by URI
+func writeContent(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "content-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for uri, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(uri))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestFilesystemStore(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"/economy/data.json":                 `{"description": {"title": "Economy"}}`,
+		"/economy/inflation/data.json":       `{"description": {"title": "Inflation"}}`,
+		"/economy/inflation/cpi/data.json":   `{}`,
+		"/economy/inflation/cpi/figure.xlsx": "xlsx",
+		"/economy/growth/chart.png":          "png",
+	})
+	defer os.RemoveAll(dir)
+
+	s := NewFilesystemStore(dir)
+
+	exists := []struct {
+		uri  string
+		want bool
+	}{
+		{"/economy", true},
+		{"/economy/data.json", true},
+		{"/economy/inflation/cpi/figure.xlsx", true},
+		{"/economy/growth", false},
+		{"/economy/growth/chart.png", true},
+		{"/business", false},
+		{"/../economy", true},
+	}
+
+	for _, tt := range exists {
+		if got, err := s.Exists(tt.uri); err != nil || got != tt.want {
+			t.Errorf("Exists(%q) = %v, %v, want %v", tt.uri, got, err, tt.want)
+		}
+	}
+
+	f, err := s.Get("/economy/inflation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(f)
+	f.Close()
+
+	if f.Name != DataFile || string(b) != `{"description": {"title": "Inflation"}}` {
+		t.Errorf("Get(/economy/inflation) = %s %q", f.Name, b)
+	}
+
+	if _, err = s.Get("/business"); err != ErrNotFound {
+		t.Errorf("Get(/business) error = %v, want ErrNotFound", err)
+	}
+
+	tree, err := s.Tree("/economy", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := model.ContentNode{URI: "/economy", Title: "Economy", HasChildren: true, Children: []model.ContentNode{
+		{URI: "/economy/growth", Title: "growth"},
+		{URI: "/economy/inflation", Title: "Inflation", HasChildren: true},
+	}}
+	if !reflect.DeepEqual(tree, want) {
+		t.Errorf("Tree(/economy, 1) = %+v, want %+v", tree, want)
+	}
+
+	if tree, err = s.Tree("/economy", -1); err != nil || tree.Count() != 4 || tree.Truncated() {
+		t.Errorf("Tree(/economy, -1) = %+v, %v", tree, err)
+	}
+
+	if _, err = s.Tree("/economy/data.json", 1); err != ErrNotFound {
+		t.Errorf("Tree of a file error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	modTime := time.Date(2017, 4, 24, 1, 49, 8, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{DataFile, "application/json; charset=utf-8"},
+		{"chart.png", "image/png"},
+		{"table.html", "text/html; charset=utf-8"},
+		{"figure", ""},
+	}
+
+	for _, tt := range tests {
+		f := &File{Name: tt.name, Size: 10, ModTime: modTime}
+		if got := f.ContentType(); got != tt.contentType {
+			t.Errorf("ContentType() for %s = %q, want %q", tt.name, got, tt.contentType)
+		}
+	}
+
+	a := &File{Name: DataFile, Size: 10, ModTime: modTime}
+	b := &File{Name: DataFile, Size: 11, ModTime: modTime}
+	c := &File{Name: DataFile, Size: 10, ModTime: modTime.Add(time.Second)}
+
+	if a.ETag() == b.ETag() || a.ETag() == c.ETag() {
+		t.Error("ETag() doesn't change with size and modification time")
+	}
+	if a.ETag() != (&File{Name: "other.json", Size: 10, ModTime: modTime}).ETag() {
+		t.Error("ETag() isn't stable for the same size and modification time")
+	}
+}
+
+func TestPageURI(t *testing.T) {
+	tests := []struct {
+		uri, want string
+	}{
+		{"/economy", "/economy"},
+		{"/economy/", "/economy"},
+		{"/economy/data.json", "/economy"},
+		{"/economy/chart.png", "/economy"},
+		{"economy/inflation", "/economy/inflation"},
+	}
+
+	for _, tt := range tests {
+		if got := PageURI(tt.uri); got != tt.want {
+			t.Errorf("PageURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package content
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"time"
+)
+
+// Store provides read access to published content and its files
+type Store interface {
+	Reader
+	// Get opens the file at uri. The URI of a page returns its data.json.
+	Get(uri string) (*File, error)
+}
+
+// ReadSeekCloser ...
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// File is content opened from a store, it must be closed after use
+type File struct {
+	ReadSeekCloser
+
+	// Name is the file name, used to choose its content type
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ETag identifies the version of the file from its size and modification
+// time, so it's cheap to compute for large files
+func (f *File) ETag() string {
+	return fmt.Sprintf(`"%x-%x"`, f.Size, f.ModTime.UnixNano())
+}
+
+// ContentType returns the MIME type for the file based on its name, or an
+// empty string if it should be detected from the content
+func (f *File) ContentType() string {
+	if f.Name == DataFile {
+		return "application/json; charset=utf-8"
+	}
+	return mime.TypeByExtension(path.Ext(f.Name))
+}
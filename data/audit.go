@@ -11,6 +11,12 @@ type AuditEventContextType string
 const (
 	// AuditEventContextUser ...
 	AuditEventContextUser AuditEventContextType = "user"
+	// AuditEventContextRole ...
+	AuditEventContextRole AuditEventContextType = "role"
+	// AuditEventContextTeam ...
+	AuditEventContextTeam AuditEventContextType = "team"
+	// AuditEventContextRelease ...
+	AuditEventContextRelease AuditEventContextType = "release"
 )
 
 // AuditEvent ...
@@ -34,6 +40,28 @@ const (
 	AuditEventPasswordChangeFailed AuditEvent = "password_change_failed"
 	// AuditEventUserRolesUpdated ...
 	AuditEventUserRolesUpdated AuditEvent = "user_roles_updated"
+	// AuditEventRoleCreated ...
+	AuditEventRoleCreated AuditEvent = "role_created"
+	// AuditEventRoleUpdated ...
+	AuditEventRoleUpdated AuditEvent = "role_updated"
+	// AuditEventRoleDeleted ...
+	AuditEventRoleDeleted AuditEvent = "role_deleted"
+	// AuditEventTeamCreated ...
+	AuditEventTeamCreated AuditEvent = "team_created"
+	// AuditEventTeamRenamed ...
+	AuditEventTeamRenamed AuditEvent = "team_renamed"
+	// AuditEventTeamDeleted ...
+	AuditEventTeamDeleted AuditEvent = "team_deleted"
+	// AuditEventTeamMemberAdded ...
+	AuditEventTeamMemberAdded AuditEvent = "team_member_added"
+	// AuditEventTeamMemberRemoved ...
+	AuditEventTeamMemberRemoved AuditEvent = "team_member_removed"
+	// AuditEventReleaseCreated ...
+	AuditEventReleaseCreated AuditEvent = "release_created"
+	// AuditEventReleaseUpdated ...
+	AuditEventReleaseUpdated AuditEvent = "release_updated"
+	// AuditEventReleasePublished ...
+	AuditEventReleasePublished AuditEvent = "release_published"
 
 	// AuditReasonNone ...
 	AuditReasonNone AuditReason = ""
@@ -54,9 +82,14 @@ type auditEvent struct {
 	Context     string                `bson:"context"`
 	Event       AuditEvent            `bson:"event"`
 	Reason      AuditReason           `bson:"reason"`
+	Detail      interface{}           `bson:"detail,omitempty"`
 }
 
 func (m *MongoDB) createAuditEvent(userID string, contextType AuditEventContextType, context string, event AuditEvent, reason AuditReason) error {
+	return m.createAuditEventWithDetail(userID, contextType, context, event, reason, nil)
+}
+
+func (m *MongoDB) createAuditEventWithDetail(userID string, contextType AuditEventContextType, context string, event AuditEvent, reason AuditReason, detail interface{}) error {
 	sess := m.New()
 	defer sess.Close()
 
@@ -67,7 +100,8 @@ func (m *MongoDB) createAuditEvent(userID string, contextType AuditEventContextT
 		Context:     context,
 		Event:       event,
 		Reason:      reason,
+		Detail:      detail,
 	}
 
-	return m.DB("florence").C("audit").Insert(&e)
+	return sess.DB("florence").C("audit").Insert(&e)
 }
@@ -15,6 +15,38 @@ var ErrCollectionAlreadyExists = errors.New("collection already exists")
 // ErrCollectionNotFound ...
 var ErrCollectionNotFound = errors.New("collection not found")
 
+// ErrCollectionPublished ...
+var ErrCollectionPublished = errors.New("collection is published")
+
+// ErrInvalidApprovalTransition ...
+var ErrInvalidApprovalTransition = errors.New("invalid approval transition")
+
+// ErrCollectionContentPending ...
+var ErrCollectionContentPending = errors.New("collection has content which isn't reviewed")
+
+// ErrCollectionApproved ...
+var ErrCollectionApproved = errors.New("collection is approved")
+
+// ErrCollectionNotDeletable ...
+var ErrCollectionNotDeletable = errors.New("collection can't be deleted")
+
+type archivedCollection struct {
+	ID         string           `bson:"_id"`
+	Collection model.Collection `bson:"collection"`
+	Deleted    time.Time        `bson:"deleted"`
+	DeletedBy  string           `bson:"deleted_by"`
+}
+
+// CollectionUpdate describes changes to a collection, nil fields are left unchanged
+type CollectionUpdate struct {
+	Name            *string
+	Type            *string
+	PublishDate     *time.Time
+	ReleaseURI      *string
+	CollectionOwner *string
+	Teams           []string
+}
+
 // GetCollection ...
 func (m *MongoDB) GetCollection(id string) (model.Collection, error) {
 	sess := m.New()
@@ -48,23 +80,105 @@ func (m *MongoDB) ListCollections() ([]model.Collection, error) {
 	return r, nil
 }
 
+// ListCollectionsForTeams returns the collections which belong to any of the
+// given teams
+func (m *MongoDB) ListCollectionsForTeams(teamIDs []string) ([]model.Collection, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r []model.Collection
+
+	err := sess.DB("florence").C("collections").Find(bson.M{"teams": bson.M{"$in": teamIDs}}).All(&r)
+	if err != nil {
+		return []model.Collection{}, err
+	}
+
+	return r, nil
+}
+
+// AddCollectionTeam ...
+func (m *MongoDB) AddCollectionTeam(collectionID, teamID string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{"_id": collectionID}, bson.M{"$addToSet": bson.M{"teams": teamID}})
+	if err == mgo.ErrNotFound {
+		return ErrCollectionNotFound
+	}
+	return err
+}
+
+// RemoveCollectionTeam ...
+func (m *MongoDB) RemoveCollectionTeam(collectionID, teamID string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{"_id": collectionID}, bson.M{"$pull": bson.M{"teams": teamID}})
+	if err == mgo.ErrNotFound {
+		return ErrCollectionNotFound
+	}
+	return err
+}
+
+// GetCollectionEvents returns the events for each collection, oldest first
+func (m *MongoDB) GetCollectionEvents(collectionIDs ...string) (map[string][]model.CollectionEvent, error) {
+	r := make(map[string][]model.CollectionEvent)
+	if len(collectionIDs) == 0 {
+		return r, nil
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	var events []model.CollectionEvent
+
+	err := sess.DB("florence").C("collection_events").Find(bson.M{"collection_id": bson.M{"$in": collectionIDs}}).Sort("created").All(&events)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range events {
+		r[e.CollectionID] = append(r[e.CollectionID], e)
+	}
+
+	return r, nil
+}
+
 // CreateCollectionEvent ...
 func (m *MongoDB) CreateCollectionEvent(event, collectionID, email string) error {
+	return m.CreateCollectionEventWithDetail(event, collectionID, email, nil)
+}
+
+// CreateCollectionEventWithDetail ...
+func (m *MongoDB) CreateCollectionEventWithDetail(event, collectionID, email string, detail interface{}) error {
 	sess := m.New()
 	defer sess.Close()
 
 	c := model.CollectionEvent{
+		Type:         event,
 		Email:        email,
 		CollectionID: collectionID,
 		Created:      time.Now(),
+		Detail:       detail,
 	}
 
 	err := sess.DB("florence").C("collection_events").Insert(&c)
 	return err
 }
 
-// CreateCollection ...
+// CreateCollection creates an unpublished collection. If releaseURI is set,
+// the collection is linked to that release and its publish date is taken
+// from the release date.
 func (m *MongoDB) CreateCollection(name, publishType string, publishDate *time.Time, owner, releaseURI string, teams []string) (string, error) {
+	if len(releaseURI) > 0 {
+		release, err := m.linkableRelease(releaseURI)
+		if err != nil {
+			return "", err
+		}
+		releaseURI = release.URI
+		publishDate = &release.ReleaseDate
+	}
+
 	sess := m.New()
 	defer sess.Close()
 
@@ -82,16 +196,21 @@ func (m *MongoDB) CreateCollection(name, publishType string, publishDate *time.T
 		return "", err
 	}
 
+	if teams == nil {
+		teams = []string{}
+	}
+
 	c := model.Collection{
 		ID:              id,
 		Name:            name,
-		PendingDeletes:  []interface{}{},
+		PendingDeletes:  []model.PendingDelete{},
 		ReleaseURI:      releaseURI,
 		Type:            publishType,
 		PublishDate:     publishDate,
 		CollectionOwner: owner,
-		Teams:           []interface{}{},
+		Teams:           teams,
 		Published:       false,
+		ApprovalStatus:  model.ApprovalNotStarted,
 	}
 
 	err = sess.DB("florence").C("collections").Insert(&c)
@@ -101,3 +220,236 @@ func (m *MongoDB) CreateCollection(name, publishType string, publishDate *time.T
 
 	return id, nil
 }
+
+// UpdateCollection applies the update to an unpublished collection, returning
+// the names of the fields which changed. A collection linked to a release
+// always takes its publish date from the release.
+func (m *MongoDB) UpdateCollection(id string, u CollectionUpdate) ([]string, error) {
+	c, err := m.GetCollection(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Published {
+		return nil, ErrCollectionPublished
+	}
+
+	if c.Approval() != model.ApprovalNotStarted {
+		return nil, ErrCollectionApproved
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	set := bson.M{}
+	var changed []string
+
+	if u.Name != nil && *u.Name != c.Name {
+		n, err := sess.DB("florence").C("collections").Find(bson.M{"name": *u.Name, "published": false, "_id": bson.M{"$ne": id}}).Count()
+		if err != nil {
+			return nil, err
+		}
+
+		if n > 0 {
+			return nil, ErrCollectionAlreadyExists
+		}
+
+		set["name"] = *u.Name
+		changed = append(changed, "name")
+	}
+
+	if u.Type != nil && *u.Type != c.Type {
+		set["type"] = *u.Type
+		changed = append(changed, "type")
+	}
+
+	releaseURI := c.ReleaseURI
+	if u.ReleaseURI != nil && len(*u.ReleaseURI) > 0 {
+		release, err := m.GetRelease(*u.ReleaseURI)
+		if err != nil {
+			return nil, err
+		}
+
+		if release.URI != c.ReleaseURI {
+			if release.Status != model.ReleaseStatusUpcoming {
+				return nil, ErrReleaseClosed
+			}
+			releaseURI = release.URI
+		}
+
+		u.PublishDate = &release.ReleaseDate
+	} else if u.ReleaseURI != nil {
+		releaseURI = ""
+	} else if len(c.ReleaseURI) > 0 {
+		u.PublishDate = nil
+	}
+
+	if u.PublishDate != nil && (c.PublishDate == nil || !u.PublishDate.Equal(*c.PublishDate)) {
+		set["publish_date"] = *u.PublishDate
+		changed = append(changed, "publishDate")
+	}
+
+	if releaseURI != c.ReleaseURI {
+		set["release_uri"] = releaseURI
+		changed = append(changed, "releaseUri")
+	}
+
+	if u.CollectionOwner != nil && *u.CollectionOwner != c.CollectionOwner {
+		set["collection_owner"] = *u.CollectionOwner
+		changed = append(changed, "collectionOwner")
+	}
+
+	if u.Teams != nil && !sameStrings(u.Teams, c.Teams) {
+		set["teams"] = u.Teams
+		changed = append(changed, "teams")
+	}
+
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	err = sess.DB("florence").C("collections").Update(bson.M{"_id": id, "published": false}, bson.M{"$set": set})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrCollectionPublished
+		}
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !containsString(b, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// CollectionDeletable returns ErrCollectionNotDeletable if the collection
+// can't be deleted, force skips the workflow checks but never allows a
+// published collection to be deleted
+func CollectionDeletable(c model.Collection, force bool) error {
+	if c.Published {
+		return ErrCollectionPublished
+	}
+
+	if c.Publishing() {
+		return ErrCollectionNotDeletable
+	}
+
+	if force {
+		return nil
+	}
+
+	switch c.Approval() {
+	case model.ApprovalInProgress, model.ApprovalComplete:
+		return ErrCollectionNotDeletable
+	}
+
+	return nil
+}
+
+// DeleteCollection archives the collection, releases its content locks and
+// records a DELETED event
+func (m *MongoDB) DeleteCollection(id, email string, force bool) error {
+	c, err := m.GetCollection(id)
+	if err != nil {
+		return err
+	}
+
+	if err = CollectionDeletable(c, force); err != nil {
+		return err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	a := archivedCollection{
+		ID:         c.ID,
+		Collection: c,
+		Deleted:    time.Now(),
+		DeletedBy:  email,
+	}
+
+	_, err = sess.DB("florence").C("collections_archive").Upsert(bson.M{"_id": c.ID}, &a)
+	if err != nil {
+		return err
+	}
+
+	err = sess.DB("florence").C("collections").Remove(bson.M{"_id": c.ID, "published": false})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrCollectionNotFound
+		}
+		return err
+	}
+
+	_, err = sess.DB("florence").C("collection_content").RemoveAll(bson.M{"collection_id": c.ID})
+	if err != nil {
+		return err
+	}
+
+	if err = m.ReleaseContentLocks(c.ID); err != nil {
+		return err
+	}
+
+	return m.CreateCollectionEvent("DELETED", c.ID, email)
+}
+
+// CountPendingContent returns the number of content items in the collection
+// which haven't been reviewed
+func (m *MongoDB) CountPendingContent(id string) (int, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	return sess.DB("florence").C("collection_content").Find(bson.M{"collection_id": id, "state": bson.M{"$ne": model.ContentStateReviewed}}).Count()
+}
+
+// TransitionApproval moves an unpublished collection to a new approval
+// state, recording the transition as a collection event
+func (m *MongoDB) TransitionApproval(id, to, event, email string) error {
+	c, err := m.GetCollection(id)
+	if err != nil {
+		return err
+	}
+
+	if c.Published {
+		return ErrCollectionPublished
+	}
+
+	if c.Publishing() {
+		return ErrPublishLeaseHeld
+	}
+
+	from := c.Approval()
+	if !model.CanTransitionApproval(from, to) {
+		return ErrInvalidApprovalTransition
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	q := bson.M{"_id": id, "published": false, "approval_status": from, "$or": []bson.M{
+		{"publish_lease": nil},
+		{"publish_lease.expires": bson.M{"$lt": time.Now()}},
+	}}
+	if from == model.ApprovalNotStarted {
+		q["approval_status"] = bson.M{"$in": []interface{}{from, "", nil}}
+	}
+
+	err = sess.DB("florence").C("collections").Update(q, bson.M{"$set": bson.M{"approval_status": to}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrInvalidApprovalTransition
+		}
+		return err
+	}
+
+	return m.CreateCollectionEventWithDetail(event, id, email, model.ApprovalTransitionDetail{From: from, To: to})
+}
@@ -0,0 +1,234 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrContentNotFound ...
+var ErrContentNotFound = errors.New("content not found")
+
+// ErrInvalidContentState ...
+var ErrInvalidContentState = errors.New("invalid content state")
+
+// ErrContentPendingDelete ...
+var ErrContentPendingDelete = errors.New("content is marked for deletion")
+
+// ErrReviewerIsEditor ...
+var ErrReviewerIsEditor = errors.New("content can't be reviewed by its last editor")
+
+// GetCollectionContent returns the content items for each collection
+func (m *MongoDB) GetCollectionContent(collectionIDs ...string) (map[string][]model.ContentItem, error) {
+	r := make(map[string][]model.ContentItem)
+	if len(collectionIDs) == 0 {
+		return r, nil
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	var items []model.ContentItem
+
+	err := sess.DB("florence").C("collection_content").Find(bson.M{"collection_id": bson.M{"$in": collectionIDs}}).Sort("uri").All(&items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range items {
+		r[i.CollectionID] = append(r[i.CollectionID], i)
+	}
+
+	return r, nil
+}
+
+// GetContentItem ...
+func (m *MongoDB) GetContentItem(collectionID, uri string) (model.ContentItem, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var i model.ContentItem
+
+	err := sess.DB("florence").C("collection_content").Find(bson.M{"collection_id": collectionID, "uri": model.CleanURI(uri)}).One(&i)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return model.ContentItem{}, ErrContentNotFound
+		}
+		return model.ContentItem{}, err
+	}
+
+	return i, nil
+}
+
+// checkContentEditable returns the collection, or an error if content in
+// the collection can't currently be changed
+func (m *MongoDB) checkContentEditable(collectionID string) (model.Collection, error) {
+	c, err := m.GetCollection(collectionID)
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	if c.Published {
+		return model.Collection{}, ErrCollectionPublished
+	}
+
+	if c.Approval() == model.ApprovalInProgress || c.Approval() == model.ApprovalComplete {
+		return model.Collection{}, ErrCollectionApproved
+	}
+
+	return c, nil
+}
+
+// EditContent creates or updates a content item, moving it to inProgress.
+// The collection must hold, or be able to take, the lock on uri, and uri
+// can't be marked for deletion by the collection.
+func (m *MongoDB) EditContent(collectionID, uri, email string) (model.ContentItem, error) {
+	c, err := m.checkContentEditable(collectionID)
+	if err != nil {
+		return model.ContentItem{}, err
+	}
+
+	uri = model.CleanURI(uri)
+
+	if _, ok := c.PendingDelete(uri); ok {
+		return model.ContentItem{}, ErrContentPendingDelete
+	}
+
+	if err = m.LockContent(c, uri, model.LockReasonContent, email); err != nil {
+		return model.ContentItem{}, err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	now := time.Now()
+	_, err = sess.DB("florence").C("collection_content").Upsert(bson.M{"collection_id": collectionID, "uri": uri}, bson.M{
+		"$set": bson.M{
+			"state":          model.ContentStateInProgress,
+			"last_edited_by": email,
+			"last_edited":    now,
+		},
+		"$unset":       bson.M{"reviewed_by": "", "reviewed": ""},
+		"$setOnInsert": bson.M{"created": now},
+	})
+	if err != nil {
+		return model.ContentItem{}, err
+	}
+
+	err = m.CreateCollectionEventWithDetail("CONTENT_EDITED", collectionID, email, contentEventDetail{uri})
+	if err != nil {
+		return model.ContentItem{}, err
+	}
+
+	return m.GetContentItem(collectionID, uri)
+}
+
+// CompleteContent moves an inProgress content item to complete
+func (m *MongoDB) CompleteContent(collectionID, uri, email string) (model.ContentItem, error) {
+	if _, err := m.checkContentEditable(collectionID); err != nil {
+		return model.ContentItem{}, err
+	}
+
+	uri = model.CleanURI(uri)
+
+	q := bson.M{"state": bson.M{"$in": model.ContentStatesBefore(model.ContentStateComplete)}}
+	return m.transitionContent(collectionID, uri, q, "CONTENT_COMPLETED", email, bson.M{
+		"state":          model.ContentStateComplete,
+		"last_edited_by": email,
+		"last_edited":    time.Now(),
+	})
+}
+
+// ReviewContent moves a complete content item to reviewed, the reviewer
+// must be a different user from the last editor
+func (m *MongoDB) ReviewContent(collectionID, uri, email string) (model.ContentItem, error) {
+	if _, err := m.checkContentEditable(collectionID); err != nil {
+		return model.ContentItem{}, err
+	}
+
+	uri = model.CleanURI(uri)
+
+	i, err := m.GetContentItem(collectionID, uri)
+	if err != nil {
+		return model.ContentItem{}, err
+	}
+
+	if i.LastEditedBy == email {
+		return model.ContentItem{}, ErrReviewerIsEditor
+	}
+
+	q := bson.M{"state": bson.M{"$in": model.ContentStatesBefore(model.ContentStateReviewed)}, "last_edited_by": bson.M{"$ne": email}}
+	return m.transitionContent(collectionID, uri, q, "CONTENT_REVIEWED", email, bson.M{
+		"state":       model.ContentStateReviewed,
+		"reviewed_by": email,
+		"reviewed":    time.Now(),
+	})
+}
+
+type contentEventDetail struct {
+	URI string `bson:"uri"`
+}
+
+// transitionContent applies set to the content item if it also matches q
+func (m *MongoDB) transitionContent(collectionID, uri string, q bson.M, event, email string, set bson.M) (model.ContentItem, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	q["collection_id"] = collectionID
+	q["uri"] = uri
+
+	err := sess.DB("florence").C("collection_content").Update(q, bson.M{"$set": set})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			if _, err = m.GetContentItem(collectionID, uri); err != nil {
+				return model.ContentItem{}, err
+			}
+			return model.ContentItem{}, ErrInvalidContentState
+		}
+		return model.ContentItem{}, err
+	}
+
+	err = m.CreateCollectionEventWithDetail(event, collectionID, email, contentEventDetail{uri})
+	if err != nil {
+		return model.ContentItem{}, err
+	}
+
+	return m.GetContentItem(collectionID, uri)
+}
+
+// RemoveContent removes the content item at uri, and any content below it,
+// from the collection and releases its locks. Content marked for deletion by
+// the collection can't be removed.
+func (m *MongoDB) RemoveContent(collectionID, uri, email string) error {
+	c, err := m.checkContentEditable(collectionID)
+	if err != nil {
+		return err
+	}
+
+	uri = model.CleanURI(uri)
+
+	if _, ok := c.PendingDelete(uri); ok {
+		return ErrContentPendingDelete
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	info, err := sess.DB("florence").C("collection_content").RemoveAll(bson.M{"collection_id": collectionID, "uri": uriWithinQuery(uri)})
+	if err != nil {
+		return err
+	}
+
+	if info.Removed == 0 {
+		return ErrContentNotFound
+	}
+
+	if err = m.UnlockContent(collectionID, uri); err != nil {
+		return err
+	}
+
+	return m.CreateCollectionEventWithDetail("CONTENT_REMOVED", collectionID, email, contentEventDetail{uri})
+}
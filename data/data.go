@@ -7,6 +7,7 @@ import (
 // MongoDB ...
 type MongoDB struct {
 	*mgo.Session
+	roleCache *roleCache
 }
 
 // NewMongoDB ...
@@ -16,5 +17,5 @@ func NewMongoDB(url string) (*MongoDB, error) {
 		return nil, err
 	}
 
-	return &MongoDB{session}, nil
+	return &MongoDB{session, newRoleCache(DefaultRoleCacheTTL)}, nil
 }
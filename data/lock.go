@@ -0,0 +1,238 @@
+package data
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrLockContention ...
+var ErrLockContention = errors.New("content lock is being changed by another request")
+
+// ContentLockedError is returned when content is locked by another collection
+type ContentLockedError struct {
+	Lock model.ContentLock
+}
+
+func (e *ContentLockedError) Error() string {
+	return "content is locked by collection " + e.Lock.CollectionName
+}
+
+// uriWithinQuery matches uri and any URI below it
+func uriWithinQuery(uri string) bson.M {
+	if uri == "/" {
+		return bson.M{"$regex": "^/"}
+	}
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(uri) + "(/|$)"}
+}
+
+// uriAncestors returns the URIs above uri, nearest first
+func uriAncestors(uri string) []string {
+	var r []string
+	for uri != "/" {
+		uri = path.Dir(uri)
+		r = append(r, uri)
+	}
+	return r
+}
+
+// overlappingLocksQuery matches locks held by other collections above or
+// below uri
+func overlappingLocksQuery(c model.Collection, uri string) bson.M {
+	return bson.M{
+		"collection_id": bson.M{"$ne": c.ID},
+		"$or": []bson.M{
+			{"_id": bson.M{"$in": uriAncestors(uri)}},
+			{"_id": bson.M{"$regex": "^" + regexp.QuoteMeta(strings.TrimSuffix(uri, "/")) + "/"}},
+		},
+	}
+}
+
+// checkOverlappingLocks returns a ContentLockedError if another unpublished
+// collection holds a lock above or below uri. Locks held by collections
+// which have since been published or deleted are removed.
+func (m *MongoDB) checkOverlappingLocks(c model.Collection, uri string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	locks := sess.DB("florence").C("content_locks")
+
+	// a delete can cover a lot of locks, so each collection holding them is
+	// only looked up once
+	var owners []string
+	err := locks.Find(overlappingLocksQuery(c, uri)).Distinct("collection_id", &owners)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range owners {
+		owner, err := m.GetCollection(id)
+		if err == nil && !owner.Published {
+			q := overlappingLocksQuery(c, uri)
+			q["collection_id"] = id
+
+			var existing model.ContentLock
+			if err = locks.Find(q).One(&existing); err != nil {
+				if err == mgo.ErrNotFound {
+					continue
+				}
+				return err
+			}
+
+			existing.CollectionName = owner.Name
+			return &ContentLockedError{existing}
+		}
+		if err != nil && err != ErrCollectionNotFound {
+			return err
+		}
+
+		if _, err = locks.RemoveAll(bson.M{"collection_id": id}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LockContent locks uri for the collection for the reason given. The lock
+// conflicts with locks held on uri, and on any URI above or below it, by
+// other collections. A lock held by a collection which has since been
+// published or deleted is taken over, otherwise a conflicting lock returns a
+// ContentLockedError.
+func (m *MongoDB) LockContent(c model.Collection, uri, reason, email string) error {
+	uri = model.CleanURI(uri)
+
+	if err := m.checkOverlappingLocks(c, uri); err != nil {
+		return err
+	}
+
+	if err := m.lockURI(c, uri, reason, email); err != nil {
+		return err
+	}
+
+	// another collection may have locked an overlapping URI since we
+	// checked, if so give up our lock rather than both holding one
+	if err := m.checkOverlappingLocks(c, uri); err != nil {
+		if err2 := m.unlockURI(c.ID, uri, reason); err2 != nil {
+			return err2
+		}
+		return err
+	}
+
+	return nil
+}
+
+// maxLockAttempts is how many times lockURI retries when the lock changes
+// under it
+const maxLockAttempts = 5
+
+// lockURI takes the lock on uri itself for the collection
+func (m *MongoDB) lockURI(c model.Collection, uri, reason, email string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	locks := sess.DB("florence").C("content_locks")
+
+	l := model.ContentLock{
+		URI:            uri,
+		CollectionID:   c.ID,
+		CollectionName: c.Name,
+		Reasons:        []string{reason},
+		LockedBy:       email,
+		Locked:         time.Now(),
+	}
+
+	for i := 0; i < maxLockAttempts; i++ {
+		err := locks.Update(bson.M{"_id": uri, "collection_id": c.ID}, bson.M{"$addToSet": bson.M{"reasons": reason}})
+		if err != mgo.ErrNotFound {
+			return err
+		}
+
+		err = locks.Insert(&l)
+		if err == nil || !mgo.IsDup(err) {
+			return err
+		}
+
+		var existing model.ContentLock
+		err = locks.Find(bson.M{"_id": uri}).One(&existing)
+		if err == mgo.ErrNotFound || err == nil && existing.CollectionID == c.ID {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		owner, err := m.GetCollection(existing.CollectionID)
+		if err == nil && !owner.Published {
+			existing.CollectionName = owner.Name
+			return &ContentLockedError{existing}
+		}
+		if err != nil && err != ErrCollectionNotFound {
+			return err
+		}
+
+		err = locks.Update(bson.M{"_id": uri, "collection_id": existing.CollectionID}, &l)
+		if err != mgo.ErrNotFound {
+			return err
+		}
+	}
+
+	return ErrLockContention
+}
+
+// unlockURI releases the collection's lock on uri itself for the reason
+// given, removing the lock if it's no longer held for any reason
+func (m *MongoDB) unlockURI(collectionID, uri, reason string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	locks := sess.DB("florence").C("content_locks")
+
+	err := locks.Update(bson.M{"_id": uri, "collection_id": collectionID}, bson.M{"$pull": bson.M{"reasons": reason}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	err = locks.Remove(bson.M{"_id": uri, "collection_id": collectionID, "reasons": bson.M{"$size": 0}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// UnlockContent releases the locks the collection holds for edited content
+// on uri and any URI below it. Locks held for pending deletes are kept.
+func (m *MongoDB) UnlockContent(collectionID, uri string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	locks := sess.DB("florence").C("content_locks")
+	q := bson.M{"_id": uriWithinQuery(model.CleanURI(uri)), "collection_id": collectionID}
+
+	_, err := locks.UpdateAll(q, bson.M{"$pull": bson.M{"reasons": model.LockReasonContent}})
+	if err != nil {
+		return err
+	}
+
+	q["reasons"] = bson.M{"$size": 0}
+	_, err = locks.RemoveAll(q)
+	return err
+}
+
+// ReleaseContentLocks releases every lock held by the collection
+func (m *MongoDB) ReleaseContentLocks(collectionID string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	_, err := sess.DB("florence").C("content_locks").RemoveAll(bson.M{"collection_id": collectionID})
+	return err
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestURIAncestors(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want []string
+	}{
+		{"/", nil},
+		{"/economy", []string{"/"}},
+		{"/economy/inflation/cpi", []string{"/economy/inflation", "/economy", "/"}},
+	}
+
+	for _, tt := range tests {
+		if got := uriAncestors(tt.uri); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("uriAncestors(%q) = %v, want %v", tt.uri, got, tt.want)
+		}
+	}
+}
+
+// matchesRegex returns true if the $regex in q matches uri
+func matchesRegex(t *testing.T, q bson.M, uri string) bool {
+	re, err := regexp.Compile(q["$regex"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return re.MatchString(uri)
+}
+
+func TestURIWithinQuery(t *testing.T) {
+	tests := []struct {
+		root, uri string
+		want      bool
+	}{
+		{"/economy", "/economy", true},
+		{"/economy", "/economy/inflation", true},
+		{"/economy", "/economyfoo", false},
+		{"/economy", "/business", false},
+		{"/", "/economy", true},
+		{"/a.b", "/axb", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesRegex(t, uriWithinQuery(tt.root), tt.uri); got != tt.want {
+			t.Errorf("uriWithinQuery(%q) matches %q = %v, want %v", tt.root, tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestOverlappingLocksQuery(t *testing.T) {
+	c := model.Collection{ID: "abc"}
+
+	// a lock overlaps if it's above or below the URI, the URI itself is
+	// handled by the lock's unique ID
+	tests := []struct {
+		uri, lock string
+		want      bool
+	}{
+		{"/economy/inflation", "/economy", true},
+		{"/economy/inflation", "/", true},
+		{"/economy/inflation", "/economy/inflation/cpi", true},
+		{"/economy/inflation", "/economy/inflation", false},
+		{"/economy/inflation", "/economy/inflationfoo", false},
+		{"/economy/inflation", "/economy/growth", false},
+		{"/economy", "/business", false},
+	}
+
+	for _, tt := range tests {
+		q := overlappingLocksQuery(c, tt.uri)
+		if q["collection_id"].(bson.M)["$ne"] != c.ID {
+			t.Fatalf("overlappingLocksQuery(%q) doesn't exclude the collection's own locks", tt.uri)
+		}
+
+		or := q["$or"].([]bson.M)
+		above := false
+		for _, a := range or[0]["_id"].(bson.M)["$in"].([]string) {
+			above = above || a == tt.lock
+		}
+		below := matchesRegex(t, or[1]["_id"].(bson.M), tt.lock)
+
+		if got := above || below; got != tt.want {
+			t.Errorf("overlappingLocksQuery(%q) matches %q = %v, want %v", tt.uri, tt.lock, got, tt.want)
+		}
+	}
+}
@@ -4,23 +4,133 @@ import (
 	"time"
 )
 
+const (
+	// CollectionTypeManual ...
+	CollectionTypeManual = "manual"
+	// CollectionTypeScheduled ...
+	CollectionTypeScheduled = "scheduled"
+
+	// ApprovalNotStarted ...
+	ApprovalNotStarted = "NOT_STARTED"
+	// ApprovalInProgress ...
+	ApprovalInProgress = "IN_PROGRESS"
+	// ApprovalComplete ...
+	ApprovalComplete = "COMPLETE"
+	// ApprovalError ...
+	ApprovalError = "ERROR"
+
+	// ContentStateInProgress ...
+	ContentStateInProgress = "inProgress"
+	// ContentStateComplete ...
+	ContentStateComplete = "complete"
+	// ContentStateReviewed ...
+	ContentStateReviewed = "reviewed"
+)
+
+// ApprovalTransitions lists the approval states each state can move to. A
+// collection can be unlocked from IN_PROGRESS so one left there by an
+// approval which didn't finish isn't stuck.
+var ApprovalTransitions = map[string][]string{
+	ApprovalNotStarted: {ApprovalInProgress},
+	ApprovalInProgress: {ApprovalComplete, ApprovalError, ApprovalNotStarted},
+	ApprovalComplete:   {ApprovalNotStarted, ApprovalError},
+	ApprovalError:      {ApprovalInProgress, ApprovalNotStarted},
+}
+
+// CanTransitionApproval returns true if a collection can move from one
+// approval state to another
+func CanTransitionApproval(from, to string) bool {
+	if len(from) == 0 {
+		from = ApprovalNotStarted
+	}
+	for _, s := range ApprovalTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCollectionType returns true if t is a valid collection type
+func IsCollectionType(t string) bool {
+	switch t {
+	case CollectionTypeManual, CollectionTypeScheduled:
+		return true
+	}
+	return false
+}
+
 // Collection ...
 type Collection struct {
-	ID              string        `bson:"_id,omitempty"`
-	Name            string        `bson:"name"`
-	CollectionOwner string        `bson:"collection_owner"`
-	PendingDeletes  []interface{} `bson:"pending_deletes"`
-	PublishDate     *time.Time    `bson:"publish_date"`
-	ReleaseURI      string        `bson:"release_uri"`
-	Teams           []interface{} `bson:"teams"`
-	Type            string        `bson:"type"`
-	Published       bool          `bson:"published"`
+	ID              string          `bson:"_id,omitempty"`
+	Name            string          `bson:"name"`
+	CollectionOwner string          `bson:"collection_owner"`
+	PendingDeletes  []PendingDelete `bson:"pending_deletes"`
+	PublishDate     *time.Time      `bson:"publish_date"`
+	ReleaseURI      string          `bson:"release_uri"`
+	Teams           []string        `bson:"teams"`
+	Type            string          `bson:"type"`
+	Published       bool            `bson:"published"`
+	ApprovalStatus  string          `bson:"approval_status"`
+	PublishLease    *PublishLease   `bson:"publish_lease,omitempty"`
+}
+
+// PublishLease is held by the process publishing a collection
+type PublishLease struct {
+	Owner   string    `bson:"owner"`
+	Expires time.Time `bson:"expires"`
+}
+
+// Publishing returns true if a process currently holds the publish lease
+func (c Collection) Publishing() bool {
+	return c.PublishLease != nil && c.PublishLease.Expires.After(time.Now())
+}
+
+// PendingDelete is published content marked for deletion when its
+// collection publishes
+type PendingDelete struct {
+	URI string `bson:"uri"`
+	// Snapshot is the tree of pages below URI when it was marked
+	Snapshot    ContentNode `bson:"snapshot"`
+	RequestedBy string      `bson:"requested_by"`
+	Requested   time.Time   `bson:"requested"`
+}
+
+// PendingDelete returns the pending delete covering uri, if any
+func (c Collection) PendingDelete(uri string) (PendingDelete, bool) {
+	for _, d := range c.PendingDeletes {
+		if URIWithin(uri, d.URI) {
+			return d, true
+		}
+	}
+	return PendingDelete{}, false
+}
+
+// Approval returns the collection's approval status, defaulting to NOT_STARTED
+func (c Collection) Approval() string {
+	if len(c.ApprovalStatus) == 0 {
+		return ApprovalNotStarted
+	}
+	return c.ApprovalStatus
 }
 
 // CollectionEvent ...
 type CollectionEvent struct {
-	ID           string    `bson:"_id,omitempty"`
-	Email        string    `bson:"email"`
-	CollectionID string    `bson:"collection_id"`
-	Created      time.Time `bson:"created"`
+	ID           string      `bson:"_id,omitempty"`
+	Type         string      `bson:"type"`
+	Email        string      `bson:"email"`
+	CollectionID string      `bson:"collection_id"`
+	Created      time.Time   `bson:"created"`
+	Detail       interface{} `bson:"detail,omitempty"`
+}
+
+// ApprovalTransitionDetail ...
+type ApprovalTransitionDetail struct {
+	From string `bson:"from" json:"from"`
+	To   string `bson:"to" json:"to"`
+}
+
+// CollectionUpdatedDetail ...
+type CollectionUpdatedDetail struct {
+	Fields []string `bson:"fields" json:"fields"`
 }
@@ -0,0 +1,54 @@
+package model
+
+import "testing"
+
+func TestCanTransitionApproval(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"", ApprovalInProgress, true},
+		{"", ApprovalComplete, false},
+		{ApprovalNotStarted, ApprovalInProgress, true},
+		{ApprovalNotStarted, ApprovalComplete, false},
+		{ApprovalNotStarted, ApprovalError, false},
+		{ApprovalInProgress, ApprovalComplete, true},
+		{ApprovalInProgress, ApprovalError, true},
+		{ApprovalInProgress, ApprovalNotStarted, true},
+		{ApprovalComplete, ApprovalNotStarted, true},
+		{ApprovalComplete, ApprovalError, true},
+		{ApprovalComplete, ApprovalInProgress, false},
+		{ApprovalError, ApprovalInProgress, true},
+		{ApprovalError, ApprovalNotStarted, true},
+		{ApprovalError, ApprovalComplete, false},
+		{"UNKNOWN", ApprovalNotStarted, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionApproval(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionApproval(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCollectionPendingDelete(t *testing.T) {
+	c := Collection{PendingDeletes: []PendingDelete{{URI: "/economy/inflation"}, {URI: "/business"}}}
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"/economy/inflation", "/economy/inflation"},
+		{"/economy/inflation/cpi/data.json", "/economy/inflation"},
+		{"/business/", "/business"},
+		{"/economy", ""},
+		{"/economy/inflationfoo", ""},
+	}
+
+	for _, tt := range tests {
+		d, ok := c.PendingDelete(tt.uri)
+		if ok != (len(tt.want) > 0) || d.URI != tt.want {
+			t.Errorf("PendingDelete(%q) = %q, %v, want %q", tt.uri, d.URI, ok, tt.want)
+		}
+	}
+}
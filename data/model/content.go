@@ -0,0 +1,115 @@
+package model
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContentItem is a page being edited within a collection
+type ContentItem struct {
+	ID           string     `bson:"_id,omitempty"`
+	CollectionID string     `bson:"collection_id"`
+	URI          string     `bson:"uri"`
+	State        string     `bson:"state"`
+	LastEditedBy string     `bson:"last_edited_by"`
+	Created      time.Time  `bson:"created"`
+	LastEdited   time.Time  `bson:"last_edited"`
+	ReviewedBy   string     `bson:"reviewed_by,omitempty"`
+	Reviewed     *time.Time `bson:"reviewed,omitempty"`
+}
+
+// ContentTransitions lists the states a content item can move to from each
+// state. Editing content always moves it back to inProgress.
+var ContentTransitions = map[string][]string{
+	ContentStateInProgress: {ContentStateInProgress, ContentStateComplete},
+	ContentStateComplete:   {ContentStateInProgress, ContentStateReviewed},
+	ContentStateReviewed:   {ContentStateInProgress},
+}
+
+// ContentStatesBefore returns the states a content item can move to state to
+// from
+func ContentStatesBefore(to string) []string {
+	var r []string
+	for from, states := range ContentTransitions {
+		for _, s := range states {
+			if s == to {
+				r = append(r, from)
+			}
+		}
+	}
+	sort.Strings(r)
+	return r
+}
+
+// CleanURI returns uri with a leading slash and without any trailing slash,
+// '.' or '..' elements
+func CleanURI(uri string) string {
+	return path.Clean("/" + uri)
+}
+
+// URIWithin returns true if uri is root or a descendant of root
+func URIWithin(uri, root string) bool {
+	uri, root = CleanURI(uri), CleanURI(root)
+	return uri == root || root == "/" || strings.HasPrefix(uri, root+"/")
+}
+
+// ContentNode is a page in the published content tree
+type ContentNode struct {
+	URI   string `bson:"uri"`
+	Title string `bson:"title"`
+	// HasChildren is set if the page has children, even if they weren't loaded
+	HasChildren bool          `bson:"has_children"`
+	Children    []ContentNode `bson:"children,omitempty"`
+}
+
+// Count returns the number of pages in the tree rooted at n
+func (n ContentNode) Count() int {
+	c := 1
+	for _, child := range n.Children {
+		c += child.Count()
+	}
+	return c
+}
+
+// Truncated returns true if the tree rooted at n has pages which weren't
+// loaded, because the tree was read to a limited depth
+func (n ContentNode) Truncated() bool {
+	if n.HasChildren && len(n.Children) == 0 {
+		return true
+	}
+	for _, child := range n.Children {
+		if child.Truncated() {
+			return true
+		}
+	}
+	return false
+}
+
+// URIs returns the URI of every page in the tree rooted at n
+func (n ContentNode) URIs() []string {
+	uris := []string{n.URI}
+	for _, child := range n.Children {
+		uris = append(uris, child.URIs()...)
+	}
+	return uris
+}
+
+const (
+	// LockReasonContent locks a URI for content edited in a collection
+	LockReasonContent = "content"
+	// LockReasonPendingDelete locks a URI marked for deletion by a collection
+	LockReasonPendingDelete = "pending_delete"
+)
+
+// ContentLock records the collection which holds a URI for editing, and the
+// reasons it holds it. The lock is released once it's held for no reason.
+type ContentLock struct {
+	URI            string    `bson:"_id"`
+	CollectionID   string    `bson:"collection_id"`
+	CollectionName string    `bson:"collection_name"`
+	Reasons        []string  `bson:"reasons"`
+	LockedBy       string    `bson:"locked_by"`
+	Locked         time.Time `bson:"locked"`
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContentStatesBefore(t *testing.T) {
+	tests := []struct {
+		to   string
+		want []string
+	}{
+		{ContentStateInProgress, []string{ContentStateComplete, ContentStateInProgress, ContentStateReviewed}},
+		{ContentStateComplete, []string{ContentStateInProgress}},
+		{ContentStateReviewed, []string{ContentStateComplete}},
+		{"unknown", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ContentStatesBefore(tt.to); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ContentStatesBefore(%q) = %v, want %v", tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCleanURI(t *testing.T) {
+	tests := []struct {
+		uri, want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"economy", "/economy"},
+		{"/economy/", "/economy"},
+		{"/economy//inflation", "/economy/inflation"},
+		{"/economy/../../etc", "/etc"},
+	}
+
+	for _, tt := range tests {
+		if got := CleanURI(tt.uri); got != tt.want {
+			t.Errorf("CleanURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestURIWithin(t *testing.T) {
+	tests := []struct {
+		uri, root string
+		want      bool
+	}{
+		{"/economy", "/economy", true},
+		{"/economy/inflation", "/economy", true},
+		{"/economy/inflation/data.json", "/economy/", true},
+		{"/economyfoo", "/economy", false},
+		{"/economy", "/economy/inflation", false},
+		{"/anything", "/", true},
+		{"/economy/../business", "/economy", false},
+	}
+
+	for _, tt := range tests {
+		if got := URIWithin(tt.uri, tt.root); got != tt.want {
+			t.Errorf("URIWithin(%q, %q) = %v, want %v", tt.uri, tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestContentNode(t *testing.T) {
+	tree := ContentNode{
+		URI:         "/economy",
+		HasChildren: true,
+		Children: []ContentNode{
+			{URI: "/economy/inflation"},
+			{URI: "/economy/growth", HasChildren: true, Children: []ContentNode{
+				{URI: "/economy/growth/gdp"},
+			}},
+		},
+	}
+
+	if n := tree.Count(); n != 4 {
+		t.Errorf("Count() = %d, want 4", n)
+	}
+
+	want := []string{"/economy", "/economy/inflation", "/economy/growth", "/economy/growth/gdp"}
+	if got := tree.URIs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("URIs() = %v, want %v", got, want)
+	}
+
+	if tree.Truncated() {
+		t.Error("Truncated() = true for a complete tree")
+	}
+
+	tree.Children[1].Children[0].HasChildren = true
+	if !tree.Truncated() {
+		t.Error("Truncated() = false for a tree with unloaded children")
+	}
+}
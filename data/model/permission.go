@@ -0,0 +1,150 @@
+package model
+
+const (
+	// PermUsersRead ...
+	PermUsersRead = "users:read"
+	// PermUsersWrite ...
+	PermUsersWrite = "users:write"
+	// PermRolesRead ...
+	PermRolesRead = "roles:read"
+	// PermRolesWrite ...
+	PermRolesWrite = "roles:write"
+	// PermTeamsRead ...
+	PermTeamsRead = "teams:read"
+	// PermTeamsWrite ...
+	PermTeamsWrite = "teams:write"
+	// PermContentRead ...
+	PermContentRead = "content:read"
+	// PermContentWrite ...
+	PermContentWrite = "content:write"
+	// PermCollectionsRead ...
+	PermCollectionsRead = "collections:read"
+	// PermCollectionsCreate ...
+	PermCollectionsCreate = "collections:create"
+	// PermCollectionsWrite ...
+	PermCollectionsWrite = "collections:write"
+	// PermCollectionsAll ...
+	PermCollectionsAll = "collections:all"
+	// PermCollectionsApprove ...
+	PermCollectionsApprove = "collections:approve"
+	// PermCollectionsPublish ...
+	PermCollectionsPublish = "collections:publish"
+	// PermReleasesRead ...
+	PermReleasesRead = "releases:read"
+	// PermReleasesWrite ...
+	PermReleasesWrite = "releases:write"
+	// PermAuditRead ...
+	PermAuditRead = "audit:read"
+	// PermDataVisPublish ...
+	PermDataVisPublish = "datavis:publish"
+)
+
+// Permissions is the catalogue of permissions which can be granted to a role
+var Permissions = map[string]string{
+	PermAdministrator:      "Administrator (legacy, see LegacyPermissions)",
+	PermEditor:             "Editor (legacy, see LegacyPermissions)",
+	PermUsersRead:          "View users and their permissions",
+	PermUsersWrite:         "Create users and change their roles",
+	PermRolesRead:          "View roles",
+	PermRolesWrite:         "Create, update and delete roles",
+	PermTeamsRead:          "View teams",
+	PermTeamsWrite:         "Create, update and delete teams",
+	PermContentRead:        "View published content",
+	PermContentWrite:       "Edit content within collections",
+	PermCollectionsRead:    "View collections",
+	PermCollectionsCreate:  "Create collections",
+	PermCollectionsWrite:   "Update collections and their teams",
+	PermCollectionsAll:     "View and update collections regardless of team",
+	PermCollectionsApprove: "Approve collections",
+	PermCollectionsPublish: "Publish collections",
+	PermReleasesRead:       "View the release calendar",
+	PermReleasesWrite:      "Create and update release calendar entries",
+	PermAuditRead:          "View the audit log",
+	PermDataVisPublish:     "Publish data visualisations",
+}
+
+// LegacyPermissions maps the original role-level permissions to the
+// equivalent set of permissions from the catalogue
+var LegacyPermissions = map[string][]string{
+	PermAdministrator: {
+		PermUsersRead,
+		PermUsersWrite,
+		PermRolesRead,
+		PermRolesWrite,
+		PermTeamsRead,
+		PermTeamsWrite,
+		PermContentRead,
+		PermContentWrite,
+		PermCollectionsRead,
+		PermCollectionsCreate,
+		PermCollectionsWrite,
+		PermCollectionsAll,
+		PermCollectionsApprove,
+		PermCollectionsPublish,
+		PermReleasesRead,
+		PermReleasesWrite,
+		PermAuditRead,
+	},
+	PermEditor: {
+		PermUsersRead,
+		PermTeamsRead,
+		PermContentRead,
+		PermContentWrite,
+		PermCollectionsRead,
+		PermCollectionsCreate,
+		PermCollectionsWrite,
+		PermReleasesRead,
+	},
+}
+
+// DataVisContentPaths are the content paths a data vis publisher may edit
+var DataVisContentPaths = []string{"/visualisations"}
+
+// DefaultRoles are created at startup if they don't already exist
+var DefaultRoles = []Role{
+	// every user holds the baseline role, it grants the access any logged in
+	// user had before roles were introduced
+	{
+		ID:   RoleBaseline,
+		Name: "Baseline",
+		Permissions: map[string]Permission{
+			PermUsersRead:       Permission{},
+			PermTeamsRead:       Permission{},
+			PermContentRead:     Permission{},
+			PermCollectionsRead: Permission{},
+		},
+	},
+	{
+		ID:   RoleAdministrator,
+		Name: "Administrator",
+		Permissions: map[string]Permission{
+			PermAdministrator: Permission{},
+		},
+	},
+	{
+		ID:   RoleEditor,
+		Name: "Editor",
+		Permissions: map[string]Permission{
+			PermEditor: Permission{},
+		},
+	},
+	{
+		ID:   RoleDataVisPublisher,
+		Name: "Data visualisation publisher",
+		Permissions: map[string]Permission{
+			PermDataVisPublish:     Permission{},
+			PermTeamsRead:          Permission{},
+			PermContentRead:        Permission{},
+			PermCollectionsRead:    Permission{},
+			PermCollectionsCreate:  Permission{CollectionTypes: []string{CollectionTypeManual}},
+			PermContentWrite:       Permission{ContentPaths: DataVisContentPaths},
+			PermCollectionsPublish: Permission{CollectionTypes: []string{CollectionTypeManual}, ContentPaths: DataVisContentPaths},
+		},
+	},
+}
+
+// IsPermission returns true if perm is in the permission catalogue
+func IsPermission(perm string) bool {
+	_, ok := Permissions[perm]
+	return ok
+}
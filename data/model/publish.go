@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// PublishRecord is the history of a single collection publish
+type PublishRecord struct {
+	ID             string    `bson:"_id"`
+	CollectionID   string    `bson:"collection_id"`
+	CollectionName string    `bson:"collection_name"`
+	CollectionType string    `bson:"collection_type"`
+	PublishedBy    string    `bson:"published_by"`
+	Started        time.Time `bson:"started"`
+	Completed      time.Time `bson:"completed"`
+	Files          int       `bson:"files"`
+	PendingDeletes int       `bson:"pending_deletes"`
+	// Teams are the teams the collection was restricted to when published
+	Teams []string `bson:"teams,omitempty"`
+}
+
+// Collection returns the parts of the published collection needed to check
+// access to the record
+func (p PublishRecord) Collection() Collection {
+	return Collection{ID: p.CollectionID, Name: p.CollectionName, Type: p.CollectionType, Teams: p.Teams, Published: true}
+}
+
+// Duration ...
+func (p PublishRecord) Duration() time.Duration {
+	return p.Completed.Sub(p.Started)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	// ReleaseStatusUpcoming ...
+	ReleaseStatusUpcoming = "upcoming"
+	// ReleaseStatusPublished ...
+	ReleaseStatusPublished = "published"
+	// ReleaseStatusCancelled ...
+	ReleaseStatusCancelled = "cancelled"
+)
+
+// Release is an entry in the release calendar. Collections linked to a
+// release take their publish date from it.
+type Release struct {
+	URI          string     `bson:"_id"`
+	Title        string     `bson:"title"`
+	ReleaseDate  time.Time  `bson:"release_date"`
+	Status       string     `bson:"status"`
+	Provisional  bool       `bson:"provisional"`
+	Confirmed    bool       `bson:"confirmed"`
+	Published    *time.Time `bson:"published,omitempty"`
+	Created      time.Time  `bson:"created"`
+	LastModified time.Time  `bson:"last_modified"`
+}
+
+// IsReleaseStatus returns true if status is a valid release status
+func IsReleaseStatus(status string) bool {
+	switch status {
+	case ReleaseStatusUpcoming, ReleaseStatusPublished, ReleaseStatusCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Team ...
+type Team struct {
+	ID      string    `bson:"_id"`
+	Name    string    `bson:"name"`
+	Members []string  `bson:"members"`
+	Created time.Time `bson:"created"`
+}
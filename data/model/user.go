@@ -12,6 +12,15 @@ const (
 
 	// PermEditor ...
 	PermEditor = "editor"
+
+	// RoleAdministrator ...
+	RoleAdministrator = "administrator"
+	// RoleEditor ...
+	RoleEditor = "editor"
+	// RoleDataVisPublisher ...
+	RoleDataVisPublisher = "dataVisPublisher"
+	// RoleBaseline is held by every user
+	RoleBaseline = "baseline"
 )
 
 // User ...
@@ -25,6 +34,23 @@ type User struct {
 	Active              bool          `bson:"active"`
 	Roles               []string      `bson:"roles"`
 	VerificationCode    string        `bson:"verification_code"`
+	LastLogin           *time.Time    `bson:"last_login,omitempty"`
+	LastLoginIP         string        `bson:"last_login_ip,omitempty"`
+	LastPasswordChange  *time.Time    `bson:"last_password_change,omitempty"`
+	LastModified        *time.Time    `bson:"last_modified,omitempty"`
+	LastModifiedBy      string        `bson:"last_modified_by,omitempty"`
+	LastAdmin           string        `bson:"last_admin,omitempty"`
+}
+
+// EffectiveRoles returns the user's roles including the baseline role
+func (u User) EffectiveRoles() []string {
+	roles := []string{RoleBaseline}
+	for _, r := range u.Roles {
+		if r != RoleBaseline {
+			roles = append(roles, r)
+		}
+	}
+	return roles
 }
 
 // Token ...
@@ -45,4 +71,34 @@ type Role struct {
 
 // Permission ...
 type Permission struct {
+	// CollectionTypes restricts the permission to the given collection types
+	CollectionTypes []string `bson:"collection_types,omitempty" json:"collectionTypes,omitempty"`
+	// ContentPaths restricts the permission to content URIs under the given paths
+	ContentPaths []string `bson:"content_paths,omitempty" json:"contentPaths,omitempty"`
+}
+
+// AllowsCollectionType returns true if the permission applies to collections of type t
+func (p Permission) AllowsCollectionType(t string) bool {
+	if len(p.CollectionTypes) == 0 {
+		return true
+	}
+	for _, v := range p.CollectionTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsContentPath returns true if the permission applies to content at uri
+func (p Permission) AllowsContentPath(uri string) bool {
+	if len(p.ContentPaths) == 0 {
+		return true
+	}
+	for _, v := range p.ContentPaths {
+		if URIWithin(uri, v) {
+			return true
+		}
+	}
+	return false
 }
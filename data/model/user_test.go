@@ -0,0 +1,50 @@
+package model
+
+import "testing"
+
+func TestPermissionAllowsCollectionType(t *testing.T) {
+	tests := []struct {
+		types []string
+		t     string
+		want  bool
+	}{
+		{nil, CollectionTypeManual, true},
+		{nil, CollectionTypeScheduled, true},
+		{[]string{CollectionTypeManual}, CollectionTypeManual, true},
+		{[]string{CollectionTypeManual}, CollectionTypeScheduled, false},
+		{[]string{CollectionTypeManual, CollectionTypeScheduled}, CollectionTypeScheduled, true},
+	}
+
+	for _, tt := range tests {
+		p := Permission{CollectionTypes: tt.types}
+		if got := p.AllowsCollectionType(tt.t); got != tt.want {
+			t.Errorf("%v.AllowsCollectionType(%q) = %v, want %v", tt.types, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionAllowsContentPath(t *testing.T) {
+	tests := []struct {
+		paths []string
+		uri   string
+		want  bool
+	}{
+		{nil, "/economy", true},
+		{[]string{"/visualisations"}, "/visualisations", true},
+		{[]string{"/visualisations"}, "/visualisations/dvc123/index.html", true},
+		{[]string{"/visualisations/"}, "/visualisations/dvc123", true},
+		{[]string{"/visualisations"}, "visualisations/dvc123", true},
+		{[]string{"/visualisations"}, "/visualisationsfoo", false},
+		{[]string{"/visualisations"}, "/visualisations/../economy", false},
+		{[]string{"/visualisations"}, "/", false},
+		{[]string{"/visualisations", "/economy"}, "/economy/inflation", true},
+		{[]string{"/"}, "/economy", true},
+	}
+
+	for _, tt := range tests {
+		p := Permission{ContentPaths: tt.paths}
+		if got := p.AllowsContentPath(tt.uri); got != tt.want {
+			t.Errorf("%v.AllowsContentPath(%q) = %v, want %v", tt.paths, tt.uri, got, tt.want)
+		}
+	}
+}
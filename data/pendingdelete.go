@@ -0,0 +1,111 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrPendingDeleteExists ...
+var ErrPendingDeleteExists = errors.New("content is already marked for deletion")
+
+// ErrPendingDeleteNotFound ...
+var ErrPendingDeleteNotFound = errors.New("content isn't marked for deletion")
+
+// ErrPendingDeleteRoot ...
+var ErrPendingDeleteRoot = errors.New("the root can't be marked for deletion")
+
+// ErrPendingDeleteTooLarge ...
+var ErrPendingDeleteTooLarge = errors.New("too much content to mark for deletion")
+
+const (
+	// MaxPendingDeleteDepth is how many levels of pages a pending delete
+	// may cover below its URI
+	MaxPendingDeleteDepth = 10
+	// MaxPendingDeleteNodes is how many pages a pending delete may cover
+	MaxPendingDeleteNodes = 1000
+)
+
+// AddPendingDelete marks content for deletion when the collection publishes.
+// Content which is already covered by, or covers, another pending delete in
+// the collection can't be marked. The snapshot must be the complete tree
+// below the URI, read to at most MaxPendingDeleteDepth levels.
+func (m *MongoDB) AddPendingDelete(collectionID string, d model.PendingDelete) error {
+	d.URI = model.CleanURI(d.URI)
+	if d.URI == "/" {
+		return ErrPendingDeleteRoot
+	}
+
+	if d.Snapshot.Truncated() || d.Snapshot.Count() > MaxPendingDeleteNodes {
+		return ErrPendingDeleteTooLarge
+	}
+
+	c, err := m.checkContentEditable(collectionID)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range c.PendingDeletes {
+		if model.URIWithin(d.URI, e.URI) || model.URIWithin(e.URI, d.URI) {
+			return ErrPendingDeleteExists
+		}
+	}
+
+	// the delete covers everything below its URI, so no other collection
+	// may edit that content until the delete is published or cancelled
+	if err = m.LockContent(c, d.URI, model.LockReasonPendingDelete, d.RequestedBy); err != nil {
+		return err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	err = sess.DB("florence").C("collections").Update(bson.M{
+		"_id":                 collectionID,
+		"published":           false,
+		"pending_deletes.uri": bson.M{"$ne": d.URI},
+	}, bson.M{"$push": bson.M{"pending_deletes": d}})
+	if err != nil {
+		if err2 := m.unlockURI(collectionID, d.URI, model.LockReasonPendingDelete); err2 != nil {
+			return err2
+		}
+		if err == mgo.ErrNotFound {
+			return ErrPendingDeleteExists
+		}
+		return err
+	}
+
+	return m.CreateCollectionEventWithDetail("DELETE_MARKED", collectionID, d.RequestedBy, contentEventDetail{d.URI})
+}
+
+// CancelPendingDelete removes the pending delete for uri from the collection
+func (m *MongoDB) CancelPendingDelete(collectionID, uri, email string) error {
+	if _, err := m.checkContentEditable(collectionID); err != nil {
+		return err
+	}
+
+	uri = model.CleanURI(uri)
+
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{
+		"_id":                 collectionID,
+		"published":           false,
+		"pending_deletes.uri": uri,
+	}, bson.M{"$pull": bson.M{"pending_deletes": bson.M{"uri": uri}}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrPendingDeleteNotFound
+		}
+		return err
+	}
+
+	if err = m.unlockURI(collectionID, uri, model.LockReasonPendingDelete); err != nil {
+		return err
+	}
+
+	return m.CreateCollectionEventWithDetail("DELETE_CANCELLED", collectionID, email, contentEventDetail{uri})
+}
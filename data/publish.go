@@ -0,0 +1,236 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrPublishLeaseHeld ...
+var ErrPublishLeaseHeld = errors.New("collection is already being published")
+
+// ErrPublishLeaseLost ...
+var ErrPublishLeaseLost = errors.New("publish lease lost")
+
+// ErrTargetLeaseHeld ...
+var ErrTargetLeaseHeld = errors.New("publish target is in use by another publisher")
+
+// ErrPublishRecordNotFound ...
+var ErrPublishRecordNotFound = errors.New("publish record not found")
+
+// ListDueCollections returns approved, unpublished scheduled collections
+// with a publish date at or before t
+func (m *MongoDB) ListDueCollections(t time.Time) ([]model.Collection, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r []model.Collection
+
+	err := sess.DB("florence").C("collections").Find(bson.M{
+		"type":            model.CollectionTypeScheduled,
+		"approval_status": model.ApprovalComplete,
+		"published":       false,
+		"publish_date":    bson.M{"$lte": t},
+	}).Sort("publish_date").All(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// AcquirePublishLease takes the publish lease for an approved, unpublished
+// collection, failing if another owner holds an unexpired lease
+func (m *MongoDB) AcquirePublishLease(id, owner string, ttl time.Duration) error {
+	sess := m.New()
+	defer sess.Close()
+
+	now := time.Now()
+	err := sess.DB("florence").C("collections").Update(bson.M{
+		"_id":             id,
+		"published":       false,
+		"approval_status": model.ApprovalComplete,
+		"$or": []bson.M{
+			{"publish_lease": nil},
+			{"publish_lease.expires": bson.M{"$lt": now}},
+		},
+	}, bson.M{"$set": bson.M{"publish_lease": model.PublishLease{Owner: owner, Expires: now.Add(ttl)}}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrPublishLeaseHeld
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RenewPublishLease extends a publish lease held by owner
+func (m *MongoDB) RenewPublishLease(id, owner string, ttl time.Duration) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{"_id": id, "published": false, "publish_lease.owner": owner}, bson.M{
+		"$set": bson.M{"publish_lease.expires": time.Now().Add(ttl)},
+	})
+	if err == mgo.ErrNotFound {
+		return ErrPublishLeaseLost
+	}
+	return err
+}
+
+// FailPublish gives up a publish lease held by owner and moves the
+// collection to the approval error state, so it isn't published again
+// until it has been re-approved
+func (m *MongoDB) FailPublish(id, owner string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{"_id": id, "published": false, "publish_lease.owner": owner}, bson.M{
+		"$set":   bson.M{"approval_status": model.ApprovalError},
+		"$unset": bson.M{"publish_lease": ""},
+	})
+	if err == mgo.ErrNotFound {
+		return ErrPublishLeaseLost
+	}
+	return err
+}
+
+// CompletePublish marks the collection as published, provided owner still
+// holds the publish lease
+func (m *MongoDB) CompletePublish(id, owner string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("collections").Update(bson.M{"_id": id, "published": false, "publish_lease.owner": owner}, bson.M{
+		"$set":   bson.M{"published": true},
+		"$unset": bson.M{"publish_lease": ""},
+	})
+	if err == mgo.ErrNotFound {
+		return ErrPublishLeaseLost
+	}
+	return err
+}
+
+// AcquireTargetLease takes the lease on a publish target, failing if another
+// owner holds an unexpired lease on it
+func (m *MongoDB) AcquireTargetLease(name, owner string, ttl time.Duration) error {
+	sess := m.New()
+	defer sess.Close()
+
+	now := time.Now()
+	_, err := sess.DB("florence").C("publish_targets").Upsert(bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"lease": nil},
+			{"lease.owner": owner},
+			{"lease.expires": bson.M{"$lt": now}},
+		},
+	}, bson.M{"$set": bson.M{"lease": model.PublishLease{Owner: owner, Expires: now.Add(ttl)}}})
+	if err != nil {
+		// the upsert tries to insert the target again if another owner
+		// holds the lease
+		if mgo.IsDup(err) {
+			return ErrTargetLeaseHeld
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RenewTargetLease extends a publish target lease held by owner
+func (m *MongoDB) RenewTargetLease(name, owner string, ttl time.Duration) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("publish_targets").Update(bson.M{"_id": name, "lease.owner": owner}, bson.M{
+		"$set": bson.M{"lease.expires": time.Now().Add(ttl)},
+	})
+	if err == mgo.ErrNotFound {
+		return ErrPublishLeaseLost
+	}
+	return err
+}
+
+// ReleaseTargetLease gives up a publish target lease held by owner
+func (m *MongoDB) ReleaseTargetLease(name, owner string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("publish_targets").Update(bson.M{"_id": name, "lease.owner": owner}, bson.M{
+		"$unset": bson.M{"lease": ""},
+	})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// CreatePublishRecord ...
+func (m *MongoDB) CreatePublishRecord(r model.PublishRecord) (string, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	id, err := GenerateRandomString(32)
+	if err != nil {
+		return "", err
+	}
+	r.ID = id
+
+	err = sess.DB("florence").C("publish_history").Insert(&r)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// ListPublishRecords returns publish history, most recent first. If teams
+// isn't nil, only collections which belonged to one of teams are included.
+func (m *MongoDB) ListPublishRecords(teams []string, skip, limit int) ([]model.PublishRecord, int, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	filter := bson.M{}
+	if teams != nil {
+		filter["teams"] = bson.M{"$in": teams}
+	}
+
+	q := sess.DB("florence").C("publish_history").Find(filter)
+
+	n, err := q.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var r []model.PublishRecord
+
+	err = q.Sort("-completed").Skip(skip).Limit(limit).All(&r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, n, nil
+}
+
+// GetPublishRecord ...
+func (m *MongoDB) GetPublishRecord(id string) (model.PublishRecord, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r model.PublishRecord
+
+	err := sess.DB("florence").C("publish_history").Find(bson.M{"_id": id}).One(&r)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return model.PublishRecord{}, ErrPublishRecordNotFound
+		}
+		return model.PublishRecord{}, err
+	}
+
+	return r, nil
+}
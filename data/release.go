@@ -0,0 +1,272 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrReleaseNotFound ...
+var ErrReleaseNotFound = errors.New("release not found")
+
+// ErrReleaseExists ...
+var ErrReleaseExists = errors.New("release already exists")
+
+// ErrReleaseClosed ...
+var ErrReleaseClosed = errors.New("release is published or cancelled")
+
+// ErrInvalidReleaseStatus ...
+var ErrInvalidReleaseStatus = errors.New("invalid release status")
+
+// ReleaseUpdate describes changes to a release, nil fields are left unchanged
+type ReleaseUpdate struct {
+	Title       *string
+	ReleaseDate *time.Time
+	Status      *string
+	Provisional *bool
+	Confirmed   *bool
+}
+
+// GetReleases returns the release calendar, soonest first
+func (m *MongoDB) GetReleases() ([]model.Release, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r []model.Release
+
+	err := sess.DB("florence").C("releases").Find(bson.M{}).Sort("release_date").All(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetRelease ...
+func (m *MongoDB) GetRelease(uri string) (model.Release, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r model.Release
+
+	err := sess.DB("florence").C("releases").Find(bson.M{"_id": model.CleanURI(uri)}).One(&r)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return model.Release{}, ErrReleaseNotFound
+		}
+		return model.Release{}, err
+	}
+
+	return r, nil
+}
+
+// CreateRelease adds an upcoming release to the calendar
+func (m *MongoDB) CreateRelease(creatorID string, r model.Release) error {
+	r.URI = model.CleanURI(r.URI)
+	if len(r.Status) == 0 {
+		r.Status = model.ReleaseStatusUpcoming
+	}
+
+	if r.Status == model.ReleaseStatusPublished || !model.IsReleaseStatus(r.Status) {
+		return ErrInvalidReleaseStatus
+	}
+
+	r.Published = nil
+	r.Created = time.Now()
+	r.LastModified = r.Created
+
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("releases").Insert(&r)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return ErrReleaseExists
+		}
+		return err
+	}
+
+	return m.createAuditEvent(creatorID, AuditEventContextRelease, r.URI, AuditEventReleaseCreated, AuditReasonNone)
+}
+
+// UpdateRelease applies the update to a release which hasn't been published.
+// If the release date changes, the publish date of each unpublished
+// collection linked to the release is moved to match.
+func (m *MongoDB) UpdateRelease(creator model.User, uri string, u ReleaseUpdate) (model.Release, error) {
+	r, err := m.GetRelease(uri)
+	if err != nil {
+		return model.Release{}, err
+	}
+
+	if r.Status == model.ReleaseStatusPublished {
+		return model.Release{}, ErrReleaseClosed
+	}
+
+	if u.Status != nil && (*u.Status == model.ReleaseStatusPublished || !model.IsReleaseStatus(*u.Status)) {
+		return model.Release{}, ErrInvalidReleaseStatus
+	}
+
+	set := bson.M{"last_modified": time.Now()}
+
+	if u.Title != nil {
+		set["title"] = *u.Title
+		r.Title = *u.Title
+	}
+
+	dateChanged := u.ReleaseDate != nil && !u.ReleaseDate.Equal(r.ReleaseDate)
+	if u.ReleaseDate != nil {
+		set["release_date"] = *u.ReleaseDate
+		r.ReleaseDate = *u.ReleaseDate
+	}
+
+	if u.Status != nil {
+		set["status"] = *u.Status
+		r.Status = *u.Status
+	}
+
+	if u.Provisional != nil {
+		set["provisional"] = *u.Provisional
+		r.Provisional = *u.Provisional
+	}
+
+	if u.Confirmed != nil {
+		set["confirmed"] = *u.Confirmed
+		r.Confirmed = *u.Confirmed
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	err = sess.DB("florence").C("releases").Update(bson.M{"_id": r.URI, "status": bson.M{"$ne": model.ReleaseStatusPublished}}, bson.M{"$set": set})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return model.Release{}, ErrReleaseClosed
+		}
+		return model.Release{}, err
+	}
+
+	if err = m.createAuditEvent(creator.ID.Hex(), AuditEventContextRelease, r.URI, AuditEventReleaseUpdated, AuditReasonNone); err != nil {
+		return model.Release{}, err
+	}
+
+	if dateChanged {
+		if err = m.followReleaseDate(r, creator.Email); err != nil {
+			return model.Release{}, err
+		}
+	}
+
+	return r, nil
+}
+
+// followReleaseDate moves the publish date of each unpublished collection
+// linked to the release to the release date
+func (m *MongoDB) followReleaseDate(r model.Release, email string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	var cols []model.Collection
+
+	err := sess.DB("florence").C("collections").Find(bson.M{
+		"release_uri":  r.URI,
+		"published":    false,
+		"publish_date": bson.M{"$ne": r.ReleaseDate},
+	}).All(&cols)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cols {
+		err = sess.DB("florence").C("collections").Update(bson.M{"_id": c.ID, "published": false}, bson.M{"$set": bson.M{"publish_date": r.ReleaseDate}})
+		if err != nil {
+			if err == mgo.ErrNotFound {
+				continue
+			}
+			return err
+		}
+
+		err = m.CreateCollectionEventWithDetail("UPDATED", c.ID, email, model.CollectionUpdatedDetail{Fields: []string{"publishDate"}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// linkableRelease returns the release if collections can still be linked to it
+func (m *MongoDB) linkableRelease(uri string) (model.Release, error) {
+	r, err := m.GetRelease(uri)
+	if err != nil {
+		return model.Release{}, err
+	}
+
+	if r.Status != model.ReleaseStatusUpcoming {
+		return model.Release{}, ErrReleaseClosed
+	}
+
+	return r, nil
+}
+
+// PublishRelease marks an upcoming release as published once every
+// collection linked to it has been published
+func (m *MongoDB) PublishRelease(uri string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	uri = model.CleanURI(uri)
+
+	n, err := sess.DB("florence").C("collections").Find(bson.M{"release_uri": uri, "published": false}).Count()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	err = sess.DB("florence").C("releases").Update(bson.M{"_id": uri, "status": model.ReleaseStatusUpcoming}, bson.M{"$set": bson.M{
+		"status":        model.ReleaseStatusPublished,
+		"published":     now,
+		"last_modified": now,
+	}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			if _, err = m.GetRelease(uri); err != nil {
+				return err
+			}
+			return ErrReleaseClosed
+		}
+		return err
+	}
+
+	return m.createAuditEvent(AuditSystemUser, AuditEventContextRelease, uri, AuditEventReleasePublished, AuditReasonNone)
+}
+
+// ListCollectionsForReleases returns the collections linked to each release
+func (m *MongoDB) ListCollectionsForReleases(uris ...string) (map[string][]model.Collection, error) {
+	r := make(map[string][]model.Collection)
+	if len(uris) == 0 {
+		return r, nil
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	var cols []model.Collection
+
+	err := sess.DB("florence").C("collections").Find(bson.M{"release_uri": bson.M{"$in": uris}}).All(&cols)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cols {
+		r[c.ReleaseURI] = append(r[c.ReleaseURI], c)
+	}
+
+	return r, nil
+}
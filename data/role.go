@@ -0,0 +1,242 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrRoleExists ...
+var ErrRoleExists = errors.New("role already exists")
+
+// ErrRoleInUse ...
+var ErrRoleInUse = errors.New("role is assigned to users")
+
+// ErrRoleProtected ...
+var ErrRoleProtected = errors.New("role can't be changed")
+
+// ErrUnknownPermission ...
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// ErrInvalidCollectionType ...
+var ErrInvalidCollectionType = errors.New("invalid collection type")
+
+// GetRoles ...
+func (m *MongoDB) GetRoles() ([]model.Role, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var r []model.Role
+
+	err := sess.DB("florence").C("roles").Find(bson.M{}).Sort("_id").All(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// CreateRole ...
+func (m *MongoDB) CreateRole(creatorID string, role model.Role) error {
+	if err := validatePermissions(role); err != nil {
+		return err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("roles").Insert(&role)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return ErrRoleExists
+		}
+		return err
+	}
+
+	m.roleCache.invalidate(role.ID)
+
+	return m.createAuditEvent(creatorID, AuditEventContextRole, role.ID, AuditEventRoleCreated, AuditReasonNone)
+}
+
+// UpdateRole ...
+func (m *MongoDB) UpdateRole(creatorID string, role model.Role) error {
+	// every user holds the baseline role, so changing it changes everyone
+	if role.ID == model.RoleBaseline {
+		return ErrRoleProtected
+	}
+
+	if err := validatePermissions(role); err != nil {
+		return err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("roles").Update(bson.M{"_id": role.ID}, bson.M{"$set": bson.M{
+		"name":        role.Name,
+		"permissions": role.Permissions,
+	}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	m.roleCache.invalidate(role.ID)
+
+	return m.createAuditEvent(creatorID, AuditEventContextRole, role.ID, AuditEventRoleUpdated, AuditReasonNone)
+}
+
+func validatePermissions(role model.Role) error {
+	for k, p := range role.Permissions {
+		if !model.IsPermission(k) {
+			return ErrUnknownPermission
+		}
+		for _, t := range p.CollectionTypes {
+			if !model.IsCollectionType(t) {
+				return ErrInvalidCollectionType
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRole removes a role which isn't assigned to any users
+func (m *MongoDB) DeleteRole(creatorID, id string) error {
+	if id == model.RoleBaseline {
+		return ErrRoleProtected
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	roles := sess.DB("florence").C("roles")
+	users := sess.DB("florence").C("users")
+
+	var role model.Role
+	err := roles.Find(bson.M{"_id": id}).One(&role)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	n, err := users.Find(bson.M{"roles": id}).Count()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return ErrRoleInUse
+	}
+
+	err = roles.Remove(bson.M{"_id": id})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	m.roleCache.invalidate(id)
+
+	// the role may have been assigned since we counted, if so put it back
+	// rather than leave users holding a role which doesn't exist
+	n, err = users.Find(bson.M{"roles": id}).Count()
+	if err == nil && n > 0 {
+		err = ErrRoleInUse
+	}
+	if err != nil {
+		if err2 := roles.Insert(&role); err2 != nil {
+			return err2
+		}
+		return err
+	}
+
+	return m.createAuditEvent(creatorID, AuditEventContextRole, id, AuditEventRoleDeleted, AuditReasonNone)
+}
+
+// EnsureDefaultRoles creates any of the default roles which don't exist
+func (m *MongoDB) EnsureDefaultRoles() error {
+	for _, role := range model.DefaultRoles {
+		_, err := m.GetRole(role.ID)
+		if err == nil {
+			continue
+		}
+		if err != ErrRoleNotFound {
+			return err
+		}
+
+		err = m.CreateRole(AuditSystemUser, role)
+		if err != nil && err != ErrRoleExists {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrationRolePermissions marks the legacy permission expansion as applied
+const migrationRolePermissions = "role_permissions"
+
+// MigrateRolePermissions expands the legacy administrator and editor
+// permissions on existing roles into their equivalent permission sets. The
+// migration is only applied once, later changes to roles are left alone.
+func (m *MongoDB) MigrateRolePermissions() error {
+	sess := m.New()
+	defer sess.Close()
+
+	n, err := sess.DB("florence").C("migrations").FindId(migrationRolePermissions).Count()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return nil
+	}
+
+	roles, err := m.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		var changed bool
+		if role.Permissions == nil {
+			role.Permissions = make(map[string]model.Permission)
+		}
+
+		for legacy, perms := range model.LegacyPermissions {
+			if _, ok := role.Permissions[legacy]; !ok {
+				continue
+			}
+			for _, p := range perms {
+				if _, ok := role.Permissions[p]; !ok {
+					role.Permissions[p] = model.Permission{}
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		err = m.UpdateRole(AuditSystemUser, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = sess.DB("florence").C("migrations").Insert(bson.M{"_id": migrationRolePermissions, "applied": time.Now()})
+	if err != nil && !mgo.IsDup(err) {
+		return err
+	}
+
+	return nil
+}
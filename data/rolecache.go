@@ -0,0 +1,89 @@
+package data
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// DefaultRoleCacheTTL ...
+const DefaultRoleCacheTTL = time.Minute
+
+var (
+	roleCacheHits   = expvar.NewInt("role_cache_hits")
+	roleCacheMisses = expvar.NewInt("role_cache_misses")
+)
+
+type cachedRole struct {
+	role    model.Role
+	expires time.Time
+}
+
+type roleCache struct {
+	sync.RWMutex
+	ttl   time.Duration
+	roles map[string]cachedRole
+}
+
+func newRoleCache(ttl time.Duration) *roleCache {
+	return &roleCache{ttl: ttl, roles: make(map[string]cachedRole)}
+}
+
+func (c *roleCache) get(id string) (model.Role, bool) {
+	c.RLock()
+	r, ok := c.roles[id]
+	c.RUnlock()
+
+	if !ok || time.Now().After(r.expires) {
+		roleCacheMisses.Add(1)
+		return model.Role{}, false
+	}
+
+	roleCacheHits.Add(1)
+	return copyRole(r.role), true
+}
+
+func (c *roleCache) set(role model.Role) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.Lock()
+	c.roles[role.ID] = cachedRole{role: copyRole(role), expires: time.Now().Add(c.ttl)}
+	c.Unlock()
+}
+
+// copyRole returns a copy of role which shares no maps or slices with it, so
+// callers can't change the cached role
+func copyRole(role model.Role) model.Role {
+	if role.Permissions == nil {
+		return role
+	}
+
+	perms := make(map[string]model.Permission, len(role.Permissions))
+	for k, p := range role.Permissions {
+		p.CollectionTypes = append([]string(nil), p.CollectionTypes...)
+		p.ContentPaths = append([]string(nil), p.ContentPaths...)
+		perms[k] = p
+	}
+	role.Permissions = perms
+
+	return role
+}
+
+func (c *roleCache) invalidate(id string) {
+	c.Lock()
+	delete(c.roles, id)
+	c.Unlock()
+}
+
+// SetRoleCacheTTL changes how long roles are cached for, a TTL of zero
+// disables the cache
+func (m *MongoDB) SetRoleCacheTTL(ttl time.Duration) {
+	m.roleCache.Lock()
+	m.roleCache.ttl = ttl
+	m.roleCache.roles = make(map[string]cachedRole)
+	m.roleCache.Unlock()
+}
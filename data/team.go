@@ -0,0 +1,227 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrTeamNotFound ...
+var ErrTeamNotFound = errors.New("team not found")
+
+// ErrTeamExists ...
+var ErrTeamExists = errors.New("team already exists")
+
+type teamRenamedDetail struct {
+	From string `bson:"from"`
+	To   string `bson:"to"`
+}
+
+type teamMemberDetail struct {
+	Email string `bson:"email"`
+}
+
+// GetTeams ...
+func (m *MongoDB) GetTeams() ([]model.Team, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var t []model.Team
+
+	err := sess.DB("florence").C("teams").Find(bson.M{}).Sort("name").All(&t)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GetTeamsForUser ...
+func (m *MongoDB) GetTeamsForUser(email string) ([]model.Team, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var t []model.Team
+
+	err := sess.DB("florence").C("teams").Find(bson.M{"members": email}).Sort("name").All(&t)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ResolveTeams converts a list of team IDs or names into team IDs
+func (m *MongoDB) ResolveTeams(teams []string) ([]string, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	ids := []string{}
+	for _, v := range teams {
+		var t model.Team
+		err := sess.DB("florence").C("teams").Find(bson.M{"$or": []bson.M{{"_id": v}, {"name": v}}}).One(&t)
+		if err != nil {
+			if err == mgo.ErrNotFound {
+				return nil, ErrTeamNotFound
+			}
+			return nil, err
+		}
+		if !containsString(ids, t.ID) {
+			ids = append(ids, t.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetTeam ...
+func (m *MongoDB) GetTeam(id string) (model.Team, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var t model.Team
+
+	err := sess.DB("florence").C("teams").Find(bson.M{"_id": id}).One(&t)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return model.Team{}, ErrTeamNotFound
+		}
+		return model.Team{}, err
+	}
+
+	return t, nil
+}
+
+// CreateTeam ...
+func (m *MongoDB) CreateTeam(creatorID, name string) (model.Team, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	n, err := sess.DB("florence").C("teams").Find(bson.M{"name": name}).Count()
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	if n > 0 {
+		return model.Team{}, ErrTeamExists
+	}
+
+	id, err := GenerateRandomString(32)
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	t := model.Team{
+		ID:      id,
+		Name:    name,
+		Members: []string{},
+		Created: time.Now(),
+	}
+
+	err = sess.DB("florence").C("teams").Insert(&t)
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	err = m.createAuditEvent(creatorID, AuditEventContextTeam, id, AuditEventTeamCreated, AuditReasonNone)
+	if err != nil {
+		return model.Team{}, err
+	}
+
+	return t, nil
+}
+
+// RenameTeam ...
+func (m *MongoDB) RenameTeam(creatorID, id, name string) error {
+	t, err := m.GetTeam(id)
+	if err != nil {
+		return err
+	}
+
+	if t.Name == name {
+		return nil
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	n, err := sess.DB("florence").C("teams").Find(bson.M{"name": name}).Count()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return ErrTeamExists
+	}
+
+	err = sess.DB("florence").C("teams").Update(bson.M{"_id": id}, bson.M{"$set": bson.M{"name": name}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+
+	return m.createAuditEventWithDetail(creatorID, AuditEventContextTeam, id, AuditEventTeamRenamed, AuditReasonNone, teamRenamedDetail{t.Name, name})
+}
+
+// DeleteTeam ...
+func (m *MongoDB) DeleteTeam(creatorID, id string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("teams").Remove(bson.M{"_id": id})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+
+	_, err = sess.DB("florence").C("collections").UpdateAll(bson.M{"teams": id}, bson.M{"$pull": bson.M{"teams": id}})
+	if err != nil {
+		return err
+	}
+
+	return m.createAuditEvent(creatorID, AuditEventContextTeam, id, AuditEventTeamDeleted, AuditReasonNone)
+}
+
+// AddTeamMember ...
+func (m *MongoDB) AddTeamMember(creatorID, id, email string) error {
+	_, err := m.GetUser(email)
+	if err != nil {
+		return err
+	}
+
+	sess := m.New()
+	defer sess.Close()
+
+	err = sess.DB("florence").C("teams").Update(bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"members": email}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+
+	return m.createAuditEventWithDetail(creatorID, AuditEventContextTeam, id, AuditEventTeamMemberAdded, AuditReasonNone, teamMemberDetail{email})
+}
+
+// RemoveTeamMember ...
+func (m *MongoDB) RemoveTeamMember(creatorID, id, email string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("teams").Update(bson.M{"_id": id}, bson.M{"$pull": bson.M{"members": email}})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+
+	return m.createAuditEventWithDetail(creatorID, AuditEventContextTeam, id, AuditEventTeamMemberRemoved, AuditReasonNone, teamMemberDetail{email})
+}
@@ -33,6 +33,12 @@ var ErrRoleNotFound = errors.New("role not found")
 // ErrUserExists ...
 var ErrUserExists = errors.New("user already exists")
 
+// ErrLastAdministrator ...
+var ErrLastAdministrator = errors.New("can't remove the last administrator")
+
+// ErrConcurrentUpdate ...
+var ErrConcurrentUpdate = errors.New("concurrent update")
+
 // GetUsers ...
 func (m *MongoDB) GetUsers() ([]model.User, error) {
 	sess := m.New()
@@ -49,7 +55,7 @@ func (m *MongoDB) GetUsers() ([]model.User, error) {
 }
 
 // CreateUser ...
-func (m *MongoDB) CreateUser(creatorID, email, name string) (err error) {
+func (m *MongoDB) CreateUser(creator model.User, email, name string) (err error) {
 	_, err = m.GetUser(email)
 	if err != ErrUserNotFound {
 		return err
@@ -63,14 +69,18 @@ func (m *MongoDB) CreateUser(creatorID, email, name string) (err error) {
 		return err
 	}
 
+	now := time.Now()
 	u := model.User{
 		ID:                  bson.NewObjectId(),
 		Active:              true,
-		Created:             time.Now(),
+		Created:             now,
 		Email:               email,
 		ForcePasswordChange: true,
 		Name:                name,
 		VerificationCode:    verificationCode,
+		LastModified:        &now,
+		LastModifiedBy:      creator.Email,
+		LastAdmin:           creator.Email,
 	}
 
 	err = sess.DB("florence").C("users").Insert(&u)
@@ -78,7 +88,7 @@ func (m *MongoDB) CreateUser(creatorID, email, name string) (err error) {
 		return err
 	}
 
-	err = m.createAuditEvent(creatorID, AuditEventContextUser, u.ID.Hex(), AuditEventUserCreated, AuditReasonNone)
+	err = m.createAuditEvent(creator.ID.Hex(), AuditEventContextUser, u.ID.Hex(), AuditEventUserCreated, AuditReasonNone)
 	if err != nil {
 		return err
 	}
@@ -88,7 +98,7 @@ func (m *MongoDB) CreateUser(creatorID, email, name string) (err error) {
 		return err
 	}
 
-	err = m.createAuditEvent(creatorID, AuditEventContextUser, u.ID.Hex(), AuditEventVerificationEmailSent, AuditReasonNone)
+	err = m.createAuditEvent(creator.ID.Hex(), AuditEventContextUser, u.ID.Hex(), AuditEventVerificationEmailSent, AuditReasonNone)
 	if err != nil {
 		return err
 	}
@@ -116,6 +126,10 @@ func (m *MongoDB) GetUser(email string) (model.User, error) {
 
 // GetRole ...
 func (m *MongoDB) GetRole(role string) (model.Role, error) {
+	if r, ok := m.roleCache.get(role); ok {
+		return r, nil
+	}
+
 	sess := m.New()
 	defer sess.Close()
 
@@ -129,6 +143,8 @@ func (m *MongoDB) GetRole(role string) (model.Role, error) {
 		return model.Role{}, err
 	}
 
+	m.roleCache.set(r)
+
 	return r, nil
 }
 
@@ -180,8 +196,15 @@ func (m *MongoDB) ChangePassword(email, old, new string) error {
 		return err
 	}
 
+	now := time.Now()
 	err = sess.DB("florence").C("users").Update(bson.M{"_id": u.ID}, bson.M{
-		"$set":   bson.M{"password": b, "force_password_change": false},
+		"$set": bson.M{
+			"password":              b,
+			"force_password_change": false,
+			"last_password_change":  now,
+			"last_modified":         now,
+			"last_modified_by":      u.Email,
+		},
 		"$unset": bson.M{"verification_code": ""},
 	})
 	if err != nil {
@@ -211,7 +234,7 @@ func (m *MongoDB) ValidateUserVerificationCode(code string) (ok bool, err error)
 }
 
 // ValidateLogin ...
-func (m *MongoDB) ValidateLogin(email, password string) (string, error) {
+func (m *MongoDB) ValidateLogin(email, password, ip string) (string, error) {
 	u, err := m.GetUser(email)
 	if err != nil {
 		err2 := m.createAuditEvent(AuditSystemUser, AuditEventContextUser, email, AuditEventUserLoginFailed, AuditReasonUserNotFound)
@@ -264,6 +287,11 @@ func (m *MongoDB) ValidateLogin(email, password string) (string, error) {
 		return "", err
 	}
 
+	err = sess.DB("florence").C("users").Update(bson.M{"_id": u.ID}, bson.M{"$set": bson.M{"last_login": time.Now(), "last_login_ip": ip}})
+	if err != nil {
+		return "", err
+	}
+
 	return token, nil
 }
 
@@ -290,21 +318,195 @@ func (m *MongoDB) UpdateTokenLastActive(token string) error {
 	return sess.DB("florence").C("tokens").Update(bson.M{"_id": token}, bson.M{"$set": bson.M{"last_active": time.Now()}})
 }
 
-// SetUserRoles ...
-func (m *MongoDB) SetUserRoles(creatorID, email string, roles ...string) error {
-	u, err := m.GetUser(email)
-	if err != nil {
-		return err
+// SetUserRoles replaces the user's roles with roles
+func (m *MongoDB) SetUserRoles(creator model.User, email string, roles ...string) error {
+	return m.updateUserRoles(creator, email, func(current []string) []string {
+		return roles
+	})
+}
+
+// UpdateUserRoles adds and removes roles, leaving any other roles the user
+// has unchanged
+func (m *MongoDB) UpdateUserRoles(creator model.User, email string, add, remove []string) error {
+	return m.updateUserRoles(creator, email, func(current []string) []string {
+		var roles []string
+		for _, r := range current {
+			if !containsString(remove, r) && !containsString(roles, r) {
+				roles = append(roles, r)
+			}
+		}
+		for _, r := range add {
+			if !containsString(roles, r) {
+				roles = append(roles, r)
+			}
+		}
+		return roles
+	})
+}
+
+type userRolesDiff struct {
+	Added   []string `bson:"added"`
+	Removed []string `bson:"removed"`
+}
+
+const maxUserRolesAttempts = 3
+
+// updateUserRoles applies fn to the user's current roles, only writing the
+// result if the roles haven't been changed by someone else in the meantime
+func (m *MongoDB) updateUserRoles(creator model.User, email string, fn func(current []string) []string) error {
+	for i := 0; i < maxUserRolesAttempts; i++ {
+		u, err := m.GetUser(email)
+		if err != nil {
+			return err
+		}
+
+		roles := fn(u.Roles)
+		if roles == nil {
+			roles = []string{}
+		}
+
+		var diff userRolesDiff
+		for _, r := range roles {
+			if !containsString(u.Roles, r) {
+				diff.Added = append(diff.Added, r)
+			}
+		}
+		for _, r := range u.Roles {
+			if !containsString(roles, r) {
+				diff.Removed = append(diff.Removed, r)
+			}
+		}
+
+		if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+			return nil
+		}
+
+		for _, r := range diff.Added {
+			if _, err = m.GetRole(r); err != nil {
+				return err
+			}
+		}
+
+		adminRoles, err := m.administratorRoles()
+		if err != nil {
+			return err
+		}
+
+		demoted := u.Active && intersects(u.Roles, adminRoles) && !intersects(roles, adminRoles)
+		if demoted {
+			n, err := m.countOtherAdministrators(u, adminRoles)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return ErrLastAdministrator
+			}
+		}
+
+		err = m.setUserRoles(creator, u, roles)
+		if err == mgo.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// another administrator may have been demoted since we counted, check
+		// again now our change is written and undo it if we were the last
+		if demoted {
+			n, err := m.countOtherAdministrators(u, adminRoles)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				if err = m.revertUserRoles(u, roles); err != nil {
+					return err
+				}
+				return ErrLastAdministrator
+			}
+		}
+
+		return m.createAuditEventWithDetail(creator.ID.Hex(), AuditEventContextUser, u.ID.Hex(), AuditEventUserRolesUpdated, AuditReasonNone, diff)
 	}
 
+	return ErrConcurrentUpdate
+}
+
+func (m *MongoDB) setUserRoles(creator model.User, u model.User, roles []string) error {
 	sess := m.New()
 	defer sess.Close()
 
-	err = sess.DB("florence").C("users").Update(bson.M{"email": email}, bson.M{"$set": bson.M{"roles": roles}})
+	return sess.DB("florence").C("users").Update(bson.M{"_id": u.ID, "roles": u.Roles}, bson.M{"$set": bson.M{
+		"roles":            roles,
+		"last_modified":    time.Now(),
+		"last_modified_by": creator.Email,
+		"last_admin":       creator.Email,
+	}})
+}
+
+// revertUserRoles restores the roles u had before they were changed to roles
+func (m *MongoDB) revertUserRoles(u model.User, roles []string) error {
+	sess := m.New()
+	defer sess.Close()
+
+	err := sess.DB("florence").C("users").Update(bson.M{"_id": u.ID, "roles": roles}, bson.M{"$set": bson.M{
+		"roles":            u.Roles,
+		"last_modified":    u.LastModified,
+		"last_modified_by": u.LastModifiedBy,
+		"last_admin":       u.LastAdmin,
+	}})
+	if err == mgo.ErrNotFound {
+		return ErrConcurrentUpdate
+	}
+	return err
+}
+
+// administratorRoles returns the IDs of the roles which allow users to
+// change other users' roles
+func (m *MongoDB) administratorRoles() ([]string, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	var roles []model.Role
+
+	err := sess.DB("florence").C("roles").Find(bson.M{"permissions." + model.PermUsersWrite: bson.M{"$exists": true}}).Select(bson.M{"_id": 1}).All(&roles)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// FIXME store user roles?
-	return m.createAuditEvent(creatorID, AuditEventContextUser, u.ID.Hex(), AuditEventUserRolesUpdated, AuditReasonNone)
+	ids := []string{}
+	for _, r := range roles {
+		ids = append(ids, r.ID)
+	}
+
+	return ids, nil
+}
+
+func (m *MongoDB) countOtherAdministrators(u model.User, adminRoles []string) (int, error) {
+	sess := m.New()
+	defer sess.Close()
+
+	return sess.DB("florence").C("users").Find(bson.M{
+		"_id":    bson.M{"$ne": u.ID},
+		"roles":  bson.M{"$in": adminRoles},
+		"active": true,
+	}).Count()
+}
+
+func intersects(a, b []string) bool {
+	for _, v := range a {
+		if containsString(b, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
 }
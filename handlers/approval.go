@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+func writeApprovalError(w http.ResponseWriter, req *http.Request, err error) {
+	log.DebugR(req, "approval error", log.Data{"error": err})
+	switch err {
+	case data.ErrCollectionNotFound:
+		w.WriteHeader(404)
+	case data.ErrCollectionPublished, data.ErrInvalidApprovalTransition, data.ErrCollectionContentPending, data.ErrPublishLeaseHeld:
+		w.WriteHeader(409)
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+	}
+}
+
+// ApproveCollection ...
+func (s *FloServer) ApproveCollection(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	n, err := s.DB.CountPendingContent(c.ID)
+	if err != nil {
+		writeApprovalError(w, req, err)
+		return
+	}
+
+	if n > 0 {
+		writeApprovalError(w, req, data.ErrCollectionContentPending)
+		return
+	}
+
+	err = s.DB.TransitionApproval(c.ID, model.ApprovalInProgress, "APPROVE_SUBMITTED", u.Email)
+	if err != nil {
+		writeApprovalError(w, req, err)
+		return
+	}
+
+	// content may have changed before the collection was locked for approval
+	n, err = s.DB.CountPendingContent(c.ID)
+	if err == nil && n > 0 {
+		err = data.ErrCollectionContentPending
+	}
+	if err == nil {
+		err = s.DB.TransitionApproval(c.ID, model.ApprovalComplete, "APPROVED", u.Email)
+	}
+	if err != nil {
+		if err2 := s.DB.TransitionApproval(c.ID, model.ApprovalError, "APPROVAL_ERROR", u.Email); err2 != nil {
+			log.ErrorR(req, err2, nil)
+		}
+		writeApprovalError(w, req, err)
+		return
+	}
+
+	updated, err := s.DB.GetCollection(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
+
+// UnlockCollection ...
+func (s *FloServer) UnlockCollection(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	err := s.DB.TransitionApproval(c.ID, model.ApprovalNotStarted, "UNLOCKED", u.Email)
+	if err != nil {
+		writeApprovalError(w, req, err)
+		return
+	}
+
+	updated, err := s.DB.GetCollection(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+const (
+	defaultBrowseTreeDepth = 2
+	maxBrowseTreeDepth     = 10
+)
+
+func newBrowseTreeOutput(n model.ContentNode) getCollectionBrowseTreeOutput {
+	o := getCollectionBrowseTreeOutput{
+		URI:         n.URI,
+		Description: getCollectionBrowseTreeOutputDescription{Title: n.Title},
+		Children:    []getCollectionBrowseTreeOutput{},
+		HasChildren: n.HasChildren,
+		ContentPath: n.URI,
+	}
+	for _, child := range n.Children {
+		o.Children = append(o.Children, newBrowseTreeOutput(child))
+	}
+	return o
+}
+
+// child returns the child node for uri, adding it if it isn't in the tree
+func (o *getCollectionBrowseTreeOutput) child(uri string) *getCollectionBrowseTreeOutput {
+	o.HasChildren = true
+	for i := range o.Children {
+		if o.Children[i].URI == uri {
+			return &o.Children[i]
+		}
+	}
+
+	o.Children = append(o.Children, getCollectionBrowseTreeOutput{
+		URI:         uri,
+		Description: getCollectionBrowseTreeOutputDescription{Title: path.Base(uri)},
+		Children:    []getCollectionBrowseTreeOutput{},
+		ContentPath: uri,
+	})
+	return &o.Children[len(o.Children)-1]
+}
+
+// overlay adds a page from the collection to the tree, creating any pages
+// between it and the root which aren't published. Pages more than depth
+// levels below the root only mark their ancestor as having children.
+func (o *getCollectionBrowseTreeOutput) overlay(uri, state string, depth int) {
+	rel := strings.TrimPrefix(strings.TrimPrefix(uri, o.URI), "/")
+
+	n := o
+	if len(rel) > 0 {
+		for i, seg := range strings.Split(rel, "/") {
+			if i == depth {
+				n.HasChildren = true
+				return
+			}
+			n = n.child(path.Join(n.URI, seg))
+		}
+	}
+
+	// a page with several files in the collection shows the least
+	// advanced state
+	if len(n.State) == 0 || contentStateOrder(state) < contentStateOrder(n.State) {
+		n.State = state
+	}
+}
+
+func contentStateOrder(state string) int {
+	switch state {
+	case model.ContentStateInProgress:
+		return 0
+	case model.ContentStateComplete:
+		return 1
+	}
+	return 2
+}
+
+// mark sets the delete marker on pages covered by a pending delete and
+// sorts children by URI
+func (o *getCollectionBrowseTreeOutput) mark(c model.Collection) {
+	_, o.DeleteMarker = c.PendingDelete(o.URI)
+
+	sort.Slice(o.Children, func(i, j int) bool {
+		return o.Children[i].URI < o.Children[j].URI
+	})
+	for i := range o.Children {
+		o.Children[i].mark(c)
+	}
+}
+
+// GetCollectionBrowseTree returns the published content tree with the
+// collection's content overlaid. The tree starts at the page given by the
+// path query parameter, defaulting to the root, and is at most depth levels
+// deep so the client can load large trees a subtree at a time.
+func (s *FloServer) GetCollectionBrowseTree(w http.ResponseWriter, req *http.Request) {
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	root := model.CleanURI(req.URL.Query().Get("path"))
+
+	depth := defaultBrowseTreeDepth
+	if v := req.URL.Query().Get("depth"); len(v) > 0 {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > maxBrowseTreeDepth {
+			log.DebugR(req, "invalid depth", log.Data{"depth": v})
+			w.WriteHeader(400)
+			return
+		}
+		depth = d
+	}
+
+	var o getCollectionBrowseTreeOutput
+
+	tree, err := s.Content.Tree(root, depth)
+	published := err == nil
+	switch err {
+	case nil:
+		o = newBrowseTreeOutput(tree)
+	case content.ErrNotFound:
+		o = getCollectionBrowseTreeOutput{
+			URI:         root,
+			Description: getCollectionBrowseTreeOutputDescription{Title: path.Base(root)},
+			Children:    []getCollectionBrowseTreeOutput{},
+			ContentPath: root,
+		}
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if root == "/" && (!published || len(o.Description.Title) == 0) {
+		o.Description.Title = "master"
+	}
+
+	items, err := s.DB.GetCollectionContent(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	var found bool
+	for _, i := range items[c.ID] {
+		uri := content.PageURI(i.URI)
+		if model.URIWithin(uri, root) {
+			o.overlay(uri, i.State, depth)
+			found = true
+		}
+	}
+
+	if !published && !found && root != "/" {
+		log.DebugR(req, "browse tree path not found", log.Data{"path": root})
+		w.WriteHeader(404)
+		return
+	}
+
+	o.mark(*c)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(b)
+}
@@ -4,58 +4,63 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/ONSdigital/dp-florence-api/auth"
 	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/dp-florence-api/publish"
 	"github.com/ONSdigital/go-ns/log"
 	"github.com/gorilla/mux"
 )
 
 type createCollectionInput struct {
-	CollectionOwner string        `json:"collectionOwner"`
-	Name            string        `json:"name"`
-	PendingDeletes  []interface{} `json:"pendingDeletes"`
-	PublishDate     *time.Time    `json:"publishDate"`
-	ReleaseURI      string        `json:"releaseUri"`
-	Teams           []interface{} `json:"teams"`
-	Type            string        `json:"type"`
+	CollectionOwner string     `json:"collectionOwner"`
+	Name            string     `json:"name"`
+	PublishDate     *time.Time `json:"publishDate"`
+	ReleaseURI      string     `json:"releaseUri"`
+	Teams           []string   `json:"teams"`
+	Type            string     `json:"type"`
 }
 
 type createCollectionOutput struct {
 	ID                    string                        `json:"id"`
 	Name                  string                        `json:"name"`
 	Type                  string                        `json:"type"`
-	Teams                 []interface{}                 `json:"teams"`
+	Teams                 []string                      `json:"teams"`
 	ApprovalStatus        string                        `json:"approvalStatus"`
 	PublishComplete       bool                          `json:"publishComplete"`
 	IsEncrypted           bool                          `json:"isEncrypted"`
-	PendingDeletes        []interface{}                 `json:"pendingDeletes"`
+	PendingDeletes        []pendingDeleteOutput         `json:"pendingDeletes"`
 	CollectionOwner       string                        `json:"collectionOwner"`
 	TimeseriesImportFiles []interface{}                 `json:"timeseriesImportFiles"`
 	Events                []createCollectionEventOutput `json:"events"`
 }
 
 type createCollectionEventOutput struct {
-	Date  time.Time `json:"date"`
-	Type  string    `json:"type"`
-	Email string    `json:"email"`
+	Date   time.Time   `json:"date"`
+	Type   string      `json:"type"`
+	Email  string      `json:"email"`
+	Detail interface{} `json:"detail,omitempty"`
 }
 
 type getCollectionOutput struct {
 	ID                    string                        `json:"id"`
 	Name                  string                        `json:"name"`
 	Type                  string                        `json:"type"`
-	Teams                 []interface{}                 `json:"teams"`
+	Teams                 []string                      `json:"teams"`
 	ApprovalStatus        string                        `json:"approvalStatus"`
-	InProgress            []interface{}                 `json:"inProgress"`
-	Complete              []interface{}                 `json:"complete"`
-	Reviewed              []interface{}                 `json:"reviewed"`
-	PendingDeletes        []interface{}                 `json:"pendingDeletes"`
+	InProgress            []contentItemOutput           `json:"inProgress"`
+	Complete              []contentItemOutput           `json:"complete"`
+	Reviewed              []contentItemOutput           `json:"reviewed"`
+	PendingDeletes        []pendingDeleteOutput         `json:"pendingDeletes"`
 	Events                []createCollectionEventOutput `json:"events"`
 	TimeseriesImportFiles []interface{}                 `json:"timeseriesImportFiles"`
 	CollectionOwner       string                        `json:"collectionOwner"`
 	PublishDate           *time.Time                    `json:"publishDate,omitempty"`
+	ReleaseURI            string                        `json:"releaseUri,omitempty"`
 	PublishComplete       bool                          `json:"publishComplete"`
 }
 
@@ -63,8 +68,10 @@ type getCollectionBrowseTreeOutput struct {
 	URI          string                                   `json:"uri"`
 	Description  getCollectionBrowseTreeOutputDescription `json:"description"`
 	Children     []getCollectionBrowseTreeOutput          `json:"children"`
+	HasChildren  bool                                     `json:"hasChildren"`
 	DeleteMarker bool                                     `json:"deleteMarker"`
 	ContentPath  string                                   `json:"contentPath"`
+	State        string                                   `json:"state,omitempty"`
 }
 
 type getCollectionBrowseTreeOutputDescription struct {
@@ -79,39 +86,126 @@ type getCollectionBrowseTreeOutputDescription struct {
 {"inProgress":[],"complete":[],"reviewed":[],"timeseriesImportFiles":[],"approvalStatus":"NOT_STARTED","pendingDeletes":[],"events":[{"date":"2017-04-24T01:49:08.096Z","type":"CREATED","email":"florence@magicroundabout.ons.gov.uk"}],"collectionOwner":"PUBLISHING_SUPPORT","id":"test-95ad38cc6b4b5b82c0cb65b38d36b342e696c53b2f8630267fe8f20e0151b84b","name":"test","type":"manual","teams":[]}
 */
 
-// ListCollections ...
-func (s *FloServer) ListCollections(w http.ResponseWriter, req *http.Request) {
-	cols, err := s.DB.ListCollections()
+type updateCollectionInput struct {
+	CollectionOwner *string    `json:"collectionOwner"`
+	Name            *string    `json:"name"`
+	PublishDate     *time.Time `json:"publishDate"`
+	ReleaseURI      *string    `json:"releaseUri"`
+	Teams           []string   `json:"teams"`
+	Type            *string    `json:"type"`
+}
+
+type collectionTeamInput struct {
+	Team string `json:"team"`
+}
+
+// teamNames returns a map of team ID to team name
+func (s *FloServer) teamNames() (map[string]string, error) {
+	teams, err := s.DB.GetTeams()
 	if err != nil {
-		log.DebugR(req, "error fetching collection", log.Data{"error": err})
-		if err == data.ErrCollectionNotFound {
-			w.WriteHeader(404)
-			return
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for _, t := range teams {
+		m[t.ID] = t.Name
+	}
+
+	return m, nil
+}
+
+func collectionTeamNames(c model.Collection, names map[string]string) []string {
+	teams := []string{}
+	for _, id := range c.Teams {
+		if n, ok := names[id]; ok {
+			teams = append(teams, n)
 		}
-		w.WriteHeader(500)
-		return
 	}
+	return teams
+}
 
-	o := []getCollectionOutput{}
+// collectionLookups holds the data shared between collections when
+// building collection output
+type collectionLookups struct {
+	teams   map[string]string
+	events  map[string][]model.CollectionEvent
+	content map[string][]model.ContentItem
+}
 
-	for _, c := range cols {
-		o = append(o, getCollectionOutput{
-			ID:                    c.ID,
-			Name:                  c.Name,
-			Type:                  c.Type,
-			Teams:                 c.Teams,
-			ApprovalStatus:        "NOT_STARTED",
-			PendingDeletes:        c.PendingDeletes,
-			CollectionOwner:       c.CollectionOwner,
-			Events:                []createCollectionEventOutput{},
-			TimeseriesImportFiles: []interface{}{},
-			InProgress:            []interface{}{},
-			Complete:              []interface{}{},
-			Reviewed:              []interface{}{},
-			PublishDate:           c.PublishDate,
-			PublishComplete:       c.Published,
+func (s *FloServer) collectionLookups(ids ...string) (collectionLookups, error) {
+	var l collectionLookups
+	var err error
+
+	if l.teams, err = s.teamNames(); err != nil {
+		return l, err
+	}
+
+	if l.events, err = s.DB.GetCollectionEvents(ids...); err != nil {
+		return l, err
+	}
+
+	if l.content, err = s.DB.GetCollectionContent(ids...); err != nil {
+		return l, err
+	}
+
+	return l, nil
+}
+
+func newCollectionEventsOutput(events []model.CollectionEvent) []createCollectionEventOutput {
+	o := []createCollectionEventOutput{}
+	for _, e := range events {
+		o = append(o, createCollectionEventOutput{
+			Date:   e.Created,
+			Type:   e.Type,
+			Email:  e.Email,
+			Detail: e.Detail,
 		})
 	}
+	return o
+}
+
+func newGetCollectionOutput(c model.Collection, l collectionLookups) getCollectionOutput {
+	o := getCollectionOutput{
+		ID:                    c.ID,
+		Name:                  c.Name,
+		Type:                  c.Type,
+		Teams:                 collectionTeamNames(c, l.teams),
+		ApprovalStatus:        c.Approval(),
+		PendingDeletes:        newPendingDeletesOutput(c.PendingDeletes),
+		CollectionOwner:       c.CollectionOwner,
+		Events:                newCollectionEventsOutput(l.events[c.ID]),
+		TimeseriesImportFiles: []interface{}{},
+		InProgress:            []contentItemOutput{},
+		Complete:              []contentItemOutput{},
+		Reviewed:              []contentItemOutput{},
+		PublishDate:           c.PublishDate,
+		ReleaseURI:            c.ReleaseURI,
+		PublishComplete:       c.Published,
+	}
+
+	for _, i := range l.content[c.ID] {
+		switch i.State {
+		case model.ContentStateInProgress:
+			o.InProgress = append(o.InProgress, newContentItemOutput(i))
+		case model.ContentStateComplete:
+			o.Complete = append(o.Complete, newContentItemOutput(i))
+		case model.ContentStateReviewed:
+			o.Reviewed = append(o.Reviewed, newContentItemOutput(i))
+		}
+	}
+
+	return o
+}
+
+func (s *FloServer) writeCollection(w http.ResponseWriter, req *http.Request, status int, c model.Collection) {
+	l, err := s.collectionLookups(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o := newGetCollectionOutput(c, l)
 
 	b, err := json.Marshal(&o)
 	if err != nil {
@@ -121,25 +215,25 @@ func (s *FloServer) ListCollections(w http.ResponseWriter, req *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	w.WriteHeader(status)
 	w.Write(b)
 }
 
-// ListPublishedCollections ...
-func (s *FloServer) ListPublishedCollections(w http.ResponseWriter, req *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(`[]`))
-}
-
-// GetCollection ...
-func (s *FloServer) GetCollection(w http.ResponseWriter, req *http.Request) {
-	id := mux.Vars(req)["collection_id"]
-	if len(id) == 0 {
-		w.WriteHeader(400)
+// ListCollections ...
+func (s *FloServer) ListCollections(w http.ResponseWriter, req *http.Request) {
+	all, err := auth.HasPermission(req.Context(), s.DB, model.PermCollectionsAll)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
 		return
 	}
 
-	c, err := s.DB.GetCollection(id)
+	var cols []model.Collection
+	if all {
+		cols, err = s.DB.ListCollections()
+	} else {
+		cols, err = s.listCollectionsForUser(req)
+	}
 	if err != nil {
 		log.DebugR(req, "error fetching collection", log.Data{"error": err})
 		if err == data.ErrCollectionNotFound {
@@ -150,21 +244,22 @@ func (s *FloServer) GetCollection(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	o := getCollectionOutput{
-		ID:                    c.ID,
-		Name:                  c.Name,
-		Type:                  c.Type,
-		Teams:                 c.Teams,
-		ApprovalStatus:        "NOT_STARTED",
-		PendingDeletes:        c.PendingDeletes,
-		CollectionOwner:       c.CollectionOwner,
-		Events:                []createCollectionEventOutput{},
-		TimeseriesImportFiles: []interface{}{},
-		InProgress:            []interface{}{},
-		Complete:              []interface{}{},
-		Reviewed:              []interface{}{},
-		PublishDate:           c.PublishDate,
-		PublishComplete:       c.Published,
+	var ids []string
+	for _, c := range cols {
+		ids = append(ids, c.ID)
+	}
+
+	l, err := s.collectionLookups(ids...)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o := []getCollectionOutput{}
+
+	for _, c := range cols {
+		o = append(o, newGetCollectionOutput(c, l))
 	}
 
 	b, err := json.Marshal(&o)
@@ -179,6 +274,60 @@ func (s *FloServer) GetCollection(w http.ResponseWriter, req *http.Request) {
 	w.Write(b)
 }
 
+// checkCollectionTeams writes an error response and returns false if a
+// collection would have no teams and the user couldn't see it
+func (s *FloServer) checkCollectionTeams(w http.ResponseWriter, req *http.Request, teams []string) bool {
+	if len(teams) > 0 {
+		return true
+	}
+
+	ok, err := auth.HasPermission(req.Context(), s.DB, model.PermCollectionsAll)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return false
+	}
+
+	if !ok {
+		log.DebugR(req, "a team is required", nil)
+		w.WriteHeader(400)
+		return false
+	}
+
+	return true
+}
+
+func (s *FloServer) listCollectionsForUser(req *http.Request) ([]model.Collection, error) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		return []model.Collection{}, nil
+	}
+
+	teams, err := s.DB.GetTeamsForUser(u.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, t := range teams {
+		ids = append(ids, t.ID)
+	}
+
+	return s.DB.ListCollectionsForTeams(ids)
+}
+
+// GetCollection ...
+func (s *FloServer) GetCollection(w http.ResponseWriter, req *http.Request) {
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, *c)
+}
+
 // CreateCollection ...
 func (s *FloServer) CreateCollection(w http.ResponseWriter, req *http.Request) {
 	b, err := ioutil.ReadAll(req.Body)
@@ -205,14 +354,54 @@ func (s *FloServer) CreateCollection(w http.ResponseWriter, req *http.Request) {
 
 	log.DebugR(req, "create collection", log.Data{"collection": input})
 
-	// TODO input.Teams
-	id, err := s.DB.CreateCollection(input.Name, input.Type, input.PublishDate, input.CollectionOwner, input.ReleaseURI, []string{})
+	if !model.IsCollectionType(input.Type) {
+		log.DebugR(req, "invalid collection type", log.Data{"type": input.Type})
+		w.WriteHeader(400)
+		return
+	}
+
+	ok, err = auth.CanCreateCollection(req.Context(), s.DB, input.Type)
 	if err != nil {
-		log.DebugR(req, "error creating collection", log.Data{"error": err})
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't create collections of this type", log.Data{"type": input.Type})
+		w.WriteHeader(403)
+		return
+	}
+
+	teams, err := s.DB.ResolveTeams(input.Teams)
+	if err != nil {
+		log.DebugR(req, "error resolving teams", log.Data{"error": err})
+		if err == data.ErrTeamNotFound {
+			w.WriteHeader(400)
+			return
+		}
 		w.WriteHeader(500)
 		return
 	}
 
+	if !s.checkCollectionTeams(w, req, teams) {
+		return
+	}
+
+	id, err := s.DB.CreateCollection(input.Name, input.Type, input.PublishDate, input.CollectionOwner, input.ReleaseURI, teams)
+	if err != nil {
+		log.DebugR(req, "error creating collection", log.Data{"error": err})
+		switch err {
+		case data.ErrReleaseNotFound:
+			w.WriteHeader(400)
+		case data.ErrReleaseClosed:
+			w.WriteHeader(409)
+		default:
+			w.WriteHeader(500)
+		}
+		return
+	}
+
 	err = s.DB.CreateCollectionEvent("CREATED", id, u.Email)
 	if err != nil {
 		log.ErrorR(req, err, nil)
@@ -220,18 +409,25 @@ func (s *FloServer) CreateCollection(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	l, err := s.collectionLookups(id)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
 	r := createCollectionOutput{
 		ID:                    id,
 		Name:                  input.Name,
 		Type:                  input.Type,
-		Teams:                 input.Teams,
-		ApprovalStatus:        "NOT_STARTED",
+		Teams:                 collectionTeamNames(model.Collection{Teams: teams}, l.teams),
+		ApprovalStatus:        model.ApprovalNotStarted,
 		PublishComplete:       false,
 		IsEncrypted:           false,
-		PendingDeletes:        input.PendingDeletes,
+		PendingDeletes:        []pendingDeleteOutput{},
 		CollectionOwner:       input.CollectionOwner,
 		TimeseriesImportFiles: []interface{}{},
-		Events:                []createCollectionEventOutput{},
+		Events:                newCollectionEventsOutput(l.events[id]),
 	}
 
 	b, err = json.Marshal(&r)
@@ -246,28 +442,253 @@ func (s *FloServer) CreateCollection(w http.ResponseWriter, req *http.Request) {
 	w.Write(b)
 }
 
-// GetCollectionBrowseTree ...
-func (s *FloServer) GetCollectionBrowseTree(w http.ResponseWriter, req *http.Request) {
-	// TODO check if collection exists, or define middleware
+// AddCollectionTeam ...
+func (s *FloServer) AddCollectionTeam(w http.ResponseWriter, req *http.Request) {
+	var input collectionTeamInput
+	if err := unmarshal(req, &input); err != nil || len(input.Team) == 0 {
+		log.DebugR(req, "invalid team", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
 
-	o := getCollectionBrowseTreeOutput{
-		URI: "",
-		Description: getCollectionBrowseTreeOutputDescription{
-			Title: "master",
-		},
-		Children:     []getCollectionBrowseTreeOutput{},
-		DeleteMarker: false,
-		ContentPath:  "/",
+	teams, err := s.DB.ResolveTeams([]string{input.Team})
+	if err != nil {
+		log.DebugR(req, "error resolving team", log.Data{"error": err})
+		if err == data.ErrTeamNotFound {
+			w.WriteHeader(400)
+			return
+		}
+		w.WriteHeader(500)
+		return
 	}
 
-	b, err := json.Marshal(&o)
+	s.updateCollectionTeam(w, req, teams[0], "TEAM_ADDED", s.DB.AddCollectionTeam)
+}
+
+// RemoveCollectionTeam ...
+func (s *FloServer) RemoveCollectionTeam(w http.ResponseWriter, req *http.Request) {
+	team := mux.Vars(req)["team_id"]
+
+	// the team may already have been deleted, in which case remove the ID as given
+	teams, err := s.DB.ResolveTeams([]string{team})
+	if err == nil {
+		team = teams[0]
+	} else if err != data.ErrTeamNotFound {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.updateCollectionTeam(w, req, team, "TEAM_REMOVED", s.DB.RemoveCollectionTeam)
+}
+
+func (s *FloServer) updateCollectionTeam(w http.ResponseWriter, req *http.Request, teamID, event string, update func(collectionID, teamID string) error) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	id := c.ID
+
+	err := update(id, teamID)
+	if err != nil {
+		log.DebugR(req, "error updating collection teams", log.Data{"error": err})
+		if err == data.ErrCollectionNotFound {
+			w.WriteHeader(404)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	err = s.DB.CreateCollectionEvent(event, id, u.Email)
 	if err != nil {
 		log.ErrorR(req, err, nil)
 		w.WriteHeader(500)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(b)
+	updated, err := s.DB.GetCollection(id)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
+
+// UpdateCollection ...
+func (s *FloServer) UpdateCollection(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	var input updateCollectionInput
+	if err := unmarshal(req, &input); err != nil {
+		log.DebugR(req, "error unmarshaling data", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	if input.Name != nil && len(*input.Name) == 0 {
+		log.DebugR(req, "collection name can't be empty", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	if input.Type != nil {
+		if !model.IsCollectionType(*input.Type) {
+			log.DebugR(req, "invalid collection type", log.Data{"type": *input.Type})
+			w.WriteHeader(400)
+			return
+		}
+
+		ok, err := auth.CanCreateCollection(req.Context(), s.DB, *input.Type)
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		if !ok {
+			log.DebugR(req, "user can't use collections of this type", log.Data{"type": *input.Type})
+			w.WriteHeader(403)
+			return
+		}
+	}
+
+	update := data.CollectionUpdate{
+		Name:            input.Name,
+		Type:            input.Type,
+		PublishDate:     input.PublishDate,
+		ReleaseURI:      input.ReleaseURI,
+		CollectionOwner: input.CollectionOwner,
+	}
+
+	if input.Teams != nil {
+		teams, err := s.DB.ResolveTeams(input.Teams)
+		if err != nil {
+			log.DebugR(req, "error resolving teams", log.Data{"error": err})
+			if err == data.ErrTeamNotFound {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(500)
+			return
+		}
+
+		if !s.checkCollectionTeams(w, req, teams) {
+			return
+		}
+		update.Teams = teams
+	}
+
+	changed, err := s.DB.UpdateCollection(c.ID, update)
+	if err != nil {
+		log.DebugR(req, "error updating collection", log.Data{"error": err})
+		switch err {
+		case data.ErrCollectionNotFound:
+			w.WriteHeader(404)
+		case data.ErrReleaseNotFound:
+			w.WriteHeader(400)
+		case data.ErrCollectionAlreadyExists, data.ErrCollectionPublished, data.ErrCollectionApproved, data.ErrReleaseClosed:
+			w.WriteHeader(409)
+		default:
+			w.WriteHeader(500)
+		}
+		return
+	}
+
+	if len(changed) > 0 {
+		err = s.DB.CreateCollectionEventWithDetail("UPDATED", c.ID, u.Email, model.CollectionUpdatedDetail{Fields: changed})
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+	}
+
+	updated, err := s.DB.GetCollection(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
+
+// DeleteCollection ...
+func (s *FloServer) DeleteCollection(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	force, _ := strconv.ParseBool(req.URL.Query().Get("force"))
+	if force {
+		ok, err := auth.HasPermission(req.Context(), s.DB, model.PermCollectionsAll)
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		if !ok {
+			log.DebugR(req, "user can't force delete collections", nil)
+			w.WriteHeader(403)
+			return
+		}
+	}
+
+	err := s.DB.DeleteCollection(c.ID, u.Email, force)
+	if err != nil {
+		log.DebugR(req, "error deleting collection", log.Data{"error": err})
+		switch err {
+		case data.ErrCollectionNotFound:
+			w.WriteHeader(404)
+		case data.ErrCollectionPublished, data.ErrCollectionNotDeletable:
+			w.WriteHeader(409)
+		default:
+			w.WriteHeader(500)
+		}
+		return
+	}
+
+	if err = os.RemoveAll(publish.CollectionDir(s.CollectionsDir, c.ID)); err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(204)
 }
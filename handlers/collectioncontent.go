@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/dp-florence-api/publish"
+	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
+)
+
+// uploadsDir is the directory within the collections directory uploads are
+// written to before being moved into a collection
+const uploadsDir = ".uploads"
+
+// contentFileURI returns the URI of the file content at uri is stored in,
+// the JSON for a page is stored in its data.json
+func contentFileURI(uri string) string {
+	uri = model.CleanURI(uri)
+	if content.PageURI(uri) == uri {
+		return path.Join(uri, content.DataFile)
+	}
+	return uri
+}
+
+func (s *FloServer) collectionPath(collectionID, uri string) string {
+	return filepath.Join(publish.CollectionDir(s.CollectionsDir, collectionID), filepath.FromSlash(model.CleanURI(uri)))
+}
+
+// collectionContentRequest returns the user, collection and URI for a
+// request to change collection content, writing an error response if the
+// user can't change content at the URI
+func (s *FloServer) collectionContentRequest(w http.ResponseWriter, req *http.Request) (*model.User, *model.Collection, string, bool) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return nil, nil, "", false
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return nil, nil, "", false
+	}
+
+	uri := mux.Vars(req)["uri"]
+	if len(uri) == 0 {
+		log.DebugR(req, "uri is required", nil)
+		w.WriteHeader(400)
+		return nil, nil, "", false
+	}
+	uri = model.CleanURI(uri)
+
+	ok, err := auth.CanEditContent(req.Context(), s.DB, uri)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return nil, nil, "", false
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't edit content at uri", log.Data{"uri": uri})
+		w.WriteHeader(403)
+		return nil, nil, "", false
+	}
+
+	return u, c, uri, true
+}
+
+// GetCollectionContent serves content from the collection's working area,
+// falling back to the published content. Content the collection marks for
+// deletion isn't found.
+func (s *FloServer) GetCollectionContent(w http.ResponseWriter, req *http.Request) {
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	uri := mux.Vars(req)["uri"]
+
+	if d, ok := c.PendingDelete(uri); ok {
+		log.DebugR(req, "content is marked for deletion", log.Data{"uri": uri, "pending_delete": d.URI})
+		w.WriteHeader(404)
+		return
+	}
+
+	working := content.NewFilesystemStore(publish.CollectionDir(s.CollectionsDir, c.ID))
+	serveContent(w, req, uri, working, s.Content)
+}
+
+// PutCollectionContent stores the request body in the collection's working
+// area and moves the page the content belongs to to inProgress
+func (s *FloServer) PutCollectionContent(w http.ResponseWriter, req *http.Request) {
+	u, c, uri, ok := s.collectionContentRequest(w, req)
+	if !ok {
+		return
+	}
+
+	fileURI := contentFileURI(uri)
+	target := s.collectionPath(c.ID, fileURI)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	// uploads are staged outside the collection's working area so a partial
+	// upload can't be published
+	staging := filepath.Join(s.CollectionsDir, uploadsDir)
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	tmp, err := ioutil.TempFile(staging, c.ID+"-")
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(0644); err == nil {
+		_, err = io.Copy(tmp, req.Body)
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		log.DebugR(req, "error reading body", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	if path.Base(fileURI) == content.DataFile {
+		b, err := ioutil.ReadFile(tmp.Name())
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		if !json.Valid(b) {
+			log.DebugR(req, "page content isn't valid json", log.Data{"uri": fileURI})
+			w.WriteHeader(400)
+			return
+		}
+	}
+
+	// the content is moved into place before the page is recorded as
+	// edited, keeping the previous version to put back if that fails
+	previous := tmp.Name() + ".previous"
+	if err = os.Rename(target, previous); err != nil && !os.IsNotExist(err) {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+	replaced := err == nil
+	defer os.Remove(previous)
+
+	if err = os.Rename(tmp.Name(), target); err != nil {
+		log.ErrorR(req, err, nil)
+		restoreContent(req, target, previous, replaced)
+		w.WriteHeader(500)
+		return
+	}
+
+	i, err := s.DB.EditContent(c.ID, content.PageURI(fileURI), u.Email)
+	if err != nil {
+		restoreContent(req, target, previous, replaced)
+		writeContentError(w, req, err)
+		return
+	}
+
+	o := newContentItemOutput(i)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// restoreContent puts back the version of a file replaced by an upload, or
+// removes the upload if there wasn't one
+func restoreContent(req *http.Request, target, previous string, replaced bool) {
+	var err error
+	if replaced {
+		err = os.Rename(previous, target)
+	} else {
+		err = os.Remove(target)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		log.ErrorR(req, err, log.Data{"path": target})
+	}
+}
+
+// DeleteCollectionContent removes the page at the URI, and any content below
+// it, from the collection. Removing one of a page's other files moves the
+// page to inProgress.
+func (s *FloServer) DeleteCollectionContent(w http.ResponseWriter, req *http.Request) {
+	u, c, uri, ok := s.collectionContentRequest(w, req)
+	if !ok {
+		return
+	}
+
+	page := content.PageURI(uri)
+	if page == uri || path.Base(uri) == content.DataFile {
+		err := s.DB.RemoveContent(c.ID, page, u.Email)
+		if err != nil {
+			writeContentError(w, req, err)
+			return
+		}
+
+		if err = os.RemoveAll(s.collectionPath(c.ID, page)); err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		w.WriteHeader(204)
+		return
+	}
+
+	target := s.collectionPath(c.ID, uri)
+	if _, err := os.Stat(target); err != nil {
+		if os.IsNotExist(err) {
+			log.DebugR(req, "file not found in collection", log.Data{"uri": uri})
+			w.WriteHeader(404)
+			return
+		}
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	// the page is recorded first, leaving the file in place if the collection
+	// can't change it
+	if _, err := s.DB.EditContent(c.ID, page, u.Email); err != nil {
+		writeContentError(w, req, err)
+		return
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import "testing"
+
+func TestContentFileURI(t *testing.T) {
+	tests := []struct {
+		uri, want string
+	}{
+		{"/economy", "/economy/data.json"},
+		{"/economy/", "/economy/data.json"},
+		{"economy/inflation", "/economy/inflation/data.json"},
+		{"/economy/data.json", "/economy/data.json"},
+		{"/economy/chart.png", "/economy/chart.png"},
+	}
+
+	for _, tt := range tests {
+		if got := contentFileURI(tt.uri); got != tt.want {
+			t.Errorf("contentFileURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+type contentItemOutput struct {
+	URI          string     `json:"uri"`
+	State        string     `json:"state"`
+	LastEditedBy string     `json:"lastEditedBy"`
+	LastEdited   time.Time  `json:"lastEdited"`
+	Created      time.Time  `json:"created"`
+	ReviewedBy   string     `json:"reviewedBy,omitempty"`
+	Reviewed     *time.Time `json:"reviewed,omitempty"`
+}
+
+func newContentItemOutput(i model.ContentItem) contentItemOutput {
+	return contentItemOutput{
+		URI:          i.URI,
+		State:        i.State,
+		LastEditedBy: i.LastEditedBy,
+		LastEdited:   i.LastEdited,
+		Created:      i.Created,
+		ReviewedBy:   i.ReviewedBy,
+		Reviewed:     i.Reviewed,
+	}
+}
+
+type contentLockedOutput struct {
+	Message        string `json:"message"`
+	URI            string `json:"uri"`
+	CollectionID   string `json:"collectionId"`
+	CollectionName string `json:"collectionName"`
+}
+
+func writeContentError(w http.ResponseWriter, req *http.Request, err error) {
+	log.DebugR(req, "content error", log.Data{"error": err})
+
+	if lErr, ok := err.(*data.ContentLockedError); ok {
+		o := contentLockedOutput{
+			Message:        lErr.Error(),
+			URI:            lErr.Lock.URI,
+			CollectionID:   lErr.Lock.CollectionID,
+			CollectionName: lErr.Lock.CollectionName,
+		}
+
+		b, err := json.Marshal(&o)
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(409)
+		w.Write(b)
+		return
+	}
+
+	switch err {
+	case data.ErrCollectionNotFound, data.ErrContentNotFound:
+		w.WriteHeader(404)
+	case data.ErrCollectionPublished, data.ErrCollectionApproved, data.ErrInvalidContentState, data.ErrReviewerIsEditor, data.ErrContentPendingDelete, data.ErrLockContention:
+		w.WriteHeader(409)
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+	}
+}
+
+// EditContent ...
+func (s *FloServer) EditContent(w http.ResponseWriter, req *http.Request) {
+	s.updateContentState(w, req, s.DB.EditContent)
+}
+
+// CompleteContent ...
+func (s *FloServer) CompleteContent(w http.ResponseWriter, req *http.Request) {
+	s.updateContentState(w, req, s.DB.CompleteContent)
+}
+
+// ReviewContent ...
+func (s *FloServer) ReviewContent(w http.ResponseWriter, req *http.Request) {
+	s.updateContentState(w, req, s.DB.ReviewContent)
+}
+
+func (s *FloServer) updateContentState(w http.ResponseWriter, req *http.Request, update func(collectionID, uri, email string) (model.ContentItem, error)) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	uri := req.URL.Query().Get("uri")
+	if len(uri) == 0 {
+		log.DebugR(req, "uri is required", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	ok, err := auth.CanEditContent(req.Context(), s.DB, uri)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't edit content at uri", log.Data{"uri": uri})
+		w.WriteHeader(403)
+		return
+	}
+
+	i, err := update(c.ID, uri, u.Email)
+	if err != nil {
+		writeContentError(w, req, err)
+		return
+	}
+
+	o := newContentItemOutput(i)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+type pendingDeleteOutput struct {
+	User         string                        `json:"user"`
+	Root         getCollectionBrowseTreeOutput `json:"root"`
+	TotalDeletes int                           `json:"totalDeletes"`
+	Requested    time.Time                     `json:"requested"`
+}
+
+func newPendingDeletesOutput(deletes []model.PendingDelete) []pendingDeleteOutput {
+	o := []pendingDeleteOutput{}
+	for _, d := range deletes {
+		o = append(o, pendingDeleteOutput{
+			User:         d.RequestedBy,
+			Root:         newSnapshotOutput(d.Snapshot),
+			TotalDeletes: d.Snapshot.Count(),
+			Requested:    d.Requested,
+		})
+	}
+	return o
+}
+
+func newSnapshotOutput(n model.ContentNode) getCollectionBrowseTreeOutput {
+	o := getCollectionBrowseTreeOutput{
+		URI:          n.URI,
+		Description:  getCollectionBrowseTreeOutputDescription{Title: n.Title},
+		Children:     []getCollectionBrowseTreeOutput{},
+		HasChildren:  n.HasChildren,
+		DeleteMarker: true,
+		ContentPath:  n.URI,
+	}
+	for _, c := range n.Children {
+		o.Children = append(o.Children, newSnapshotOutput(c))
+	}
+	return o
+}
+
+func writePendingDeleteError(w http.ResponseWriter, req *http.Request, err error) {
+	if _, ok := err.(*data.ContentLockedError); ok {
+		writeContentError(w, req, err)
+		return
+	}
+
+	log.DebugR(req, "pending delete error", log.Data{"error": err})
+	switch err {
+	case data.ErrCollectionNotFound, data.ErrPendingDeleteNotFound, content.ErrNotFound:
+		w.WriteHeader(404)
+	case data.ErrCollectionPublished, data.ErrCollectionApproved, data.ErrPendingDeleteExists:
+		w.WriteHeader(409)
+	case data.ErrPendingDeleteRoot, data.ErrPendingDeleteTooLarge:
+		w.WriteHeader(400)
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+	}
+}
+
+// AddPendingDelete ...
+func (s *FloServer) AddPendingDelete(w http.ResponseWriter, req *http.Request) {
+	s.updatePendingDelete(w, req, func(c model.Collection, uri, email string) error {
+		if model.CleanURI(uri) == "/" {
+			return data.ErrPendingDeleteRoot
+		}
+
+		snapshot, err := s.Content.Tree(uri, data.MaxPendingDeleteDepth)
+		if err != nil {
+			return err
+		}
+
+		return s.DB.AddPendingDelete(c.ID, model.PendingDelete{
+			URI:         snapshot.URI,
+			Snapshot:    snapshot,
+			RequestedBy: email,
+			Requested:   time.Now(),
+		})
+	})
+}
+
+// CancelPendingDelete ...
+func (s *FloServer) CancelPendingDelete(w http.ResponseWriter, req *http.Request) {
+	s.updatePendingDelete(w, req, func(c model.Collection, uri, email string) error {
+		return s.DB.CancelPendingDelete(c.ID, uri, email)
+	})
+}
+
+func (s *FloServer) updatePendingDelete(w http.ResponseWriter, req *http.Request, update func(c model.Collection, uri, email string) error) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	uri := req.URL.Query().Get("uri")
+	if len(uri) == 0 {
+		log.DebugR(req, "uri is required", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	ok, err := auth.CanEditContent(req.Context(), s.DB, uri)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't edit content at uri", log.Data{"uri": uri})
+		w.WriteHeader(403)
+		return
+	}
+
+	if err = update(*c, uri, u.Email); err != nil {
+		writePendingDeleteError(w, req, err)
+		return
+	}
+
+	updated, err := s.DB.GetCollection(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
@@ -1,8 +1,22 @@
 package handlers
 
-import "github.com/ONSdigital/dp-florence-api/data"
+import (
+	"net"
+
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/publish"
+)
 
 // FloServer ...
 type FloServer struct {
-	DB *data.MongoDB
+	DB        *data.MongoDB
+	Publisher *publish.Publisher
+	Content   content.Store
+
+	// CollectionsDir holds the working area for each collection's content
+	CollectionsDir string
+
+	// TrustedProxies are the proxies whose X-Forwarded-For header is honoured
+	TrustedProxies []*net.IPNet
 }
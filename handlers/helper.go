@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
 )
 
 func unmarshal(req *http.Request, i interface{}) error {
@@ -16,3 +18,72 @@ func unmarshal(req *http.Request, i interface{}) error {
 
 	return json.Unmarshal(b, &i)
 }
+
+// ParseTrustedProxies parses a comma separated list of IP addresses and
+// CIDR ranges
+func ParseTrustedProxies(v string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if len(p) == 0 {
+			continue
+		}
+
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func (s *FloServer) trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range s.TrustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the address of the client, X-Forwarded-For is only
+// honoured when the request came through a trusted proxy
+func (s *FloServer) remoteIP(req *http.Request) string {
+	addr, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		addr = req.RemoteAddr
+	}
+
+	if !s.trustedProxy(addr) {
+		return addr
+	}
+
+	// walk back through the proxies until we reach one we don't trust
+	hops := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if len(hop) == 0 {
+			break
+		}
+		addr = hop
+		if !s.trustedProxy(hop) {
+			break
+		}
+	}
+
+	return addr
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		v    string
+		want []string
+		err  bool
+	}{
+		{"", nil, false},
+		{"10.0.0.1", []string{"10.0.0.1/32"}, false},
+		{"10.0.0.0/8, ::1", []string{"10.0.0.0/8", "::1/128"}, false},
+		{"10.0.0.1,,", []string{"10.0.0.1/32"}, false},
+		{"not an ip", nil, true},
+		{"10.0.0.0/33", nil, true},
+	}
+
+	for _, tt := range tests {
+		nets, err := ParseTrustedProxies(tt.v)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseTrustedProxies(%q) error = %v", tt.v, err)
+			continue
+		}
+
+		var got []string
+		for _, n := range nets {
+			got = append(got, n.String())
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseTrustedProxies(%q) = %v, want %v", tt.v, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseTrustedProxies(%q) = %v, want %v", tt.v, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &FloServer{TrustedProxies: proxies}
+
+	tests := []struct {
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"192.0.2.1:1234", "", "192.0.2.1"},
+		{"192.0.2.1:1234", "198.51.100.1", "192.0.2.1"},
+		{"10.0.0.1:1234", "", "10.0.0.1"},
+		{"10.0.0.1:1234", "198.51.100.1", "198.51.100.1"},
+		{"10.0.0.1:1234", "203.0.113.9, 198.51.100.1, 10.0.0.2", "198.51.100.1"},
+		{"10.0.0.1:1234", "10.0.0.3, 10.0.0.2", "10.0.0.3"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if len(tt.forwarded) > 0 {
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+
+		if got := s.remoteIP(req); got != tt.want {
+			t.Errorf("remoteIP(%s, X-Forwarded-For: %q) = %q, want %q", tt.remoteAddr, tt.forwarded, got, tt.want)
+		}
+	}
+}
@@ -37,7 +37,7 @@ func (s *FloServer) Login(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	token, err := s.DB.ValidateLogin(input.Email, input.Password)
+	token, err := s.DB.ValidateLogin(input.Email, input.Password, s.remoteIP(req))
 	if err != nil {
 		log.DebugR(req, "invalid username or password", log.Data{"error": err})
 
@@ -1,32 +1,50 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
+	"github.com/ONSdigital/dp-florence-api/content"
 	"github.com/ONSdigital/go-ns/log"
 	"github.com/gorilla/mux"
 )
 
-// MasterData ...
+// MasterData serves published content by URI. Files are streamed from the
+// content store, with conditional and range requests handled by
+// http.ServeContent.
 func (s *FloServer) MasterData(w http.ResponseWriter, req *http.Request) {
 	uri := mux.Vars(req)["uri"]
 
 	log.DebugR(req, "master data uri", log.Data{"uri": uri})
 
-	m := map[string]interface{}{
-		"uri": uri,
-	}
+	serveContent(w, req, uri, s.Content)
+}
+
+// serveContent serves the file at uri from the first store which has it
+func serveContent(w http.ResponseWriter, req *http.Request, uri string, stores ...content.Store) {
+	var f *content.File
+	var err error
 
-	b, err := json.Marshal(&m)
+	for _, store := range stores {
+		if f, err = store.Get(uri); err != content.ErrNotFound {
+			break
+		}
+	}
 	if err != nil {
-		log.DebugR(req, "error marshaling json", log.Data{"error": err})
+		if err == content.ErrNotFound {
+			log.DebugR(req, "content not found", log.Data{"uri": uri})
+			w.WriteHeader(404)
+			return
+		}
+		log.ErrorR(req, err, nil)
 		w.WriteHeader(500)
 		return
 	}
+	defer f.Close()
 
-	_, err = w.Write(b)
-	if err != nil {
-		log.DebugR(req, "error writing response", log.Data{"error": err})
+	w.Header().Set("ETag", f.ETag())
+	if ct := f.ContentType(); len(ct) > 0 {
+		w.Header().Set("Content-Type", ct)
 	}
+
+	http.ServeContent(w, req, f.Name, f.ModTime, f)
 }
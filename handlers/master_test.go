@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/content"
+)
+
+// contentStore creates a filesystem store holding files keyed by URI
+func contentStore(t *testing.T, files map[string]string) *content.FilesystemStore {
+	dir, err := ioutil.TempDir("", "master-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for uri, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(uri))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return content.NewFilesystemStore(dir)
+}
+
+func TestServeContent(t *testing.T) {
+	published := contentStore(t, map[string]string{
+		"/economy/data.json": `{"type": "taxonomy_landing_page"}`,
+		"/economy/chart.png": "png",
+	})
+	defer os.RemoveAll(published.Root)
+
+	working := contentStore(t, map[string]string{
+		"/economy/inflation/data.json": `{"type": "bulletin"}`,
+	})
+	defer os.RemoveAll(working.Root)
+
+	tests := []struct {
+		method, uri string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"GET", "/economy", 200, "application/json; charset=utf-8", `{"type": "taxonomy_landing_page"}`},
+		{"GET", "/economy/data.json", 200, "application/json; charset=utf-8", `{"type": "taxonomy_landing_page"}`},
+		{"GET", "/economy/chart.png", 200, "image/png", "png"},
+		{"HEAD", "/economy", 200, "application/json; charset=utf-8", ""},
+		{"GET", "/economy/inflation", 200, "application/json; charset=utf-8", `{"type": "bulletin"}`},
+		{"GET", "/business", 404, "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/master"+tt.uri, nil)
+		w := httptest.NewRecorder()
+
+		serveContent(w, req, tt.uri, published, working)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.uri, w.Code, tt.status)
+			continue
+		}
+		if tt.status != 200 {
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s %s Content-Type = %q, want %q", tt.method, tt.uri, ct, tt.contentType)
+		}
+		if len(w.Header().Get("ETag")) == 0 {
+			t.Errorf("%s %s has no ETag", tt.method, tt.uri)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s %s body = %q, want %q", tt.method, tt.uri, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestServeContentNotModified(t *testing.T) {
+	published := contentStore(t, map[string]string{
+		"/economy/data.json": `{"type": "taxonomy_landing_page"}`,
+	})
+	defer os.RemoveAll(published.Root)
+
+	w := httptest.NewRecorder()
+	serveContent(w, httptest.NewRequest("GET", "/master/economy", nil), "/economy", published)
+
+	etag := w.Header().Get("ETag")
+	if w.Code != 200 || len(etag) == 0 {
+		t.Fatalf("status = %d, ETag = %q", w.Code, etag)
+	}
+
+	req := httptest.NewRequest("GET", "/master/economy", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	serveContent(w, req, "/economy", published)
+
+	if w.Code != 304 {
+		t.Errorf("status with matching If-None-Match = %d, want 304", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/master/economy", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+	serveContent(w, req, "/economy", published)
+
+	if w.Code != 200 {
+		t.Errorf("status with another If-None-Match = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+type meOutput struct {
+	Name              string       `json:"name"`
+	Email             string       `json:"email"`
+	Admin             bool         `json:"admin"`
+	Editor            bool         `json:"editor"`
+	DataVisPublisher  bool         `json:"dataVisPublisher"`
+	Roles             []roleOutput `json:"roles"`
+	Permissions       []string     `json:"permissions"`
+	Teams             []string     `json:"teams"`
+	SessionExpiryDate time.Time    `json:"sessionExpiryDate"`
+}
+
+// Me ...
+func (s *FloServer) Me(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	perms, ok := auth.PermissionsFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "permissions not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	o := meOutput{
+		Name:              u.Name,
+		Email:             u.Email,
+		Admin:             perms.Has(model.PermAdministrator),
+		Editor:            perms.Has(model.PermEditor),
+		DataVisPublisher:  perms.Has(model.PermDataVisPublish),
+		Roles:             []roleOutput{},
+		Permissions:       []string{},
+		Teams:             []string{},
+		SessionExpiryDate: time.Now().Add(auth.SessionTimeout),
+	}
+
+	for _, r := range u.EffectiveRoles() {
+		role, err := s.DB.GetRole(r)
+		if err != nil {
+			if err == data.ErrRoleNotFound {
+				continue
+			}
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+		o.Roles = append(o.Roles, newRoleOutput(role))
+	}
+
+	teams, err := s.DB.GetTeamsForUser(u.Email)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+	for _, t := range teams {
+		o.Teams = append(o.Teams, t.Name)
+	}
+
+	for k := range perms {
+		o.Permissions = append(o.Permissions, k)
+	}
+	sort.Strings(o.Permissions)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
@@ -25,13 +25,28 @@ type roleOutput struct {
 	Permissions map[string]permissionOutput `json:"permissions"`
 }
 
-type permissionOutput map[string]interface{}
+type permissionOutput struct {
+	CollectionTypes []string `json:"collectionTypes,omitempty"`
+	ContentPaths    []string `json:"contentPaths,omitempty"`
+}
+
+func newPermissionOutput(p model.Permission) permissionOutput {
+	return permissionOutput{
+		CollectionTypes: p.CollectionTypes,
+		ContentPaths:    p.ContentPaths,
+	}
+}
 
 type permissionsInput struct {
 	Email            string `json:"email"`
 	Admin            bool   `json:"admin"`
 	Editor           bool   `json:"editor"`
 	DataVisPublisher bool   `json:"dataVisPublisher"`
+
+	// Roles are assigned in addition to those implied by the flags above
+	Roles []string `json:"roles"`
+	// Merge adds roles to those the user already has instead of replacing them
+	Merge bool `json:"merge"`
 }
 
 // GetPermissions ...
@@ -73,10 +88,11 @@ func (s *FloServer) GetPermissions(w http.ResponseWriter, req *http.Request) {
 			if k == model.PermAdministrator {
 				p.Admin = true
 			} else if k == model.PermEditor {
-				// FIXME handle data vis permission
 				p.Editor = true
+			} else if k == model.PermDataVisPublish {
+				p.DataVisPublisher = true
 			}
-			rO.Permissions[k] = permissionOutput{}
+			rO.Permissions[k] = newPermissionOutput(role.Permissions[k])
 		}
 		p.Roles = append(p.Roles, rO)
 	}
@@ -113,24 +129,44 @@ func (s *FloServer) UpdatePermissions(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	var roles []string
+	add := append([]string{}, input.Roles...)
+	var remove []string
 
-	if input.Admin {
-		roles = append(roles, "administrator")
+	flags := []struct {
+		role string
+		set  bool
+	}{
+		{model.RoleAdministrator, input.Admin},
+		{model.RoleEditor, input.Editor},
+		{model.RoleDataVisPublisher, input.DataVisPublisher},
 	}
-	if input.Editor {
-		roles = append(roles, "editor")
+	for _, f := range flags {
+		if f.set {
+			add = append(add, f.role)
+		} else if !input.Merge {
+			remove = append(remove, f.role)
+		}
 	}
-	// FIXME handle data vis users
 
-	err := s.DB.SetUserRoles(creator.ID.Hex(), input.Email, roles...)
+	var err error
+	if input.Roles != nil && !input.Merge {
+		err = s.DB.SetUserRoles(*creator, input.Email, add...)
+	} else {
+		err = s.DB.UpdateUserRoles(*creator, input.Email, add, remove)
+	}
 	if err != nil {
-		log.ErrorR(req, err, nil)
-		if err == data.ErrUserExists {
+		log.DebugR(req, "error updating user roles", log.Data{"error": err})
+		switch err {
+		case data.ErrUserNotFound:
+			w.WriteHeader(404)
+		case data.ErrRoleNotFound:
 			w.WriteHeader(400)
-			return
+		case data.ErrLastAdministrator, data.ErrConcurrentUpdate:
+			w.WriteHeader(409)
+		default:
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
 		}
-		w.WriteHeader(500)
 		return
 	}
 
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/dp-florence-api/publish"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+type publishFailuresOutput struct {
+	Failures []publish.CheckFailure `json:"failures"`
+}
+
+// PublishCollection ...
+func (s *FloServer) PublishCollection(w http.ResponseWriter, req *http.Request) {
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not in context", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	c, ok := auth.CollectionFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "collection not in context", nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if c.Type != model.CollectionTypeManual {
+		log.DebugR(req, "only manual collections can be published on demand", log.Data{"type": c.Type})
+		w.WriteHeader(409)
+		return
+	}
+
+	if c.Published {
+		log.DebugR(req, "collection already published", nil)
+		w.WriteHeader(409)
+		return
+	}
+
+	if c.Approval() != model.ApprovalComplete {
+		log.DebugR(req, "collection not approved", log.Data{"approval_status": c.Approval()})
+		w.WriteHeader(409)
+		return
+	}
+
+	ok, err := auth.CanPublishCollection(req.Context(), s.DB, *c)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't publish collection", nil)
+		w.WriteHeader(403)
+		return
+	}
+
+	err = s.Publisher.Publish(c.ID, u.Email)
+	if err != nil {
+		log.DebugR(req, "error publishing collection", log.Data{"error": err})
+
+		if cErr, ok := err.(*publish.CheckError); ok {
+			o := publishFailuresOutput{cErr.Failures}
+
+			b, err := json.Marshal(&o)
+			if err != nil {
+				log.ErrorR(req, err, nil)
+				w.WriteHeader(500)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(409)
+			w.Write(b)
+			return
+		}
+
+		if err == data.ErrPublishLeaseHeld || err == data.ErrTargetLeaseHeld {
+			w.WriteHeader(409)
+			return
+		}
+
+		w.WriteHeader(500)
+		return
+	}
+
+	updated, err := s.DB.GetCollection(c.ID)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeCollection(w, req, 200, updated)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type publishedCollectionOutput struct {
+	ID               string    `json:"id"`
+	CollectionID     string    `json:"collectionId"`
+	Name             string    `json:"name"`
+	Type             string    `json:"type"`
+	PublishedBy      string    `json:"publishedBy"`
+	PublishStartDate time.Time `json:"publishStartDate"`
+	PublishEndDate   time.Time `json:"publishEndDate"`
+	Duration         int64     `json:"duration"`
+	Files            int       `json:"files"`
+	PendingDeletes   int       `json:"pendingDeletes"`
+}
+
+func newPublishedCollectionOutput(r model.PublishRecord) publishedCollectionOutput {
+	return publishedCollectionOutput{
+		ID:               r.ID,
+		CollectionID:     r.CollectionID,
+		Name:             r.CollectionName,
+		Type:             r.CollectionType,
+		PublishedBy:      r.PublishedBy,
+		PublishStartDate: r.Started,
+		PublishEndDate:   r.Completed,
+		Duration:         int64(r.Duration() / time.Millisecond),
+		Files:            r.Files,
+		PendingDeletes:   r.PendingDeletes,
+	}
+}
+
+// accessibleTeams returns the IDs of the user's teams, or nil if the user
+// can access collections regardless of team
+func (s *FloServer) accessibleTeams(req *http.Request) ([]string, error) {
+	ok, err := auth.HasPermission(req.Context(), s.DB, model.PermCollectionsAll)
+	if err != nil || ok {
+		return nil, err
+	}
+
+	ids := []string{}
+
+	u, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		return ids, nil
+	}
+
+	teams, err := s.DB.GetTeamsForUser(u.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range teams {
+		ids = append(ids, t.ID)
+	}
+
+	return ids, nil
+}
+
+// ListPublishedCollections ...
+func (s *FloServer) ListPublishedCollections(w http.ResponseWriter, req *http.Request) {
+	page, size := 1, defaultPageSize
+
+	if v := req.URL.Query().Get("page"); len(v) > 0 {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 1 {
+			log.DebugR(req, "invalid page", log.Data{"page": v})
+			w.WriteHeader(400)
+			return
+		}
+		page = p
+	}
+
+	if v := req.URL.Query().Get("size"); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			log.DebugR(req, "invalid page size", log.Data{"size": v})
+			w.WriteHeader(400)
+			return
+		}
+		size = n
+	}
+
+	teams, err := s.accessibleTeams(req)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	records, total, err := s.DB.ListPublishRecords(teams, (page-1)*size, size)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o := []publishedCollectionOutput{}
+	for _, r := range records {
+		o = append(o, newPublishedCollectionOutput(r))
+	}
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Write(b)
+}
+
+// GetPublishedCollection ...
+func (s *FloServer) GetPublishedCollection(w http.ResponseWriter, req *http.Request) {
+	r, err := s.DB.GetPublishRecord(mux.Vars(req)["publish_id"])
+	if err != nil {
+		log.DebugR(req, "error fetching publish record", log.Data{"error": err})
+		if err == data.ErrPublishRecordNotFound {
+			w.WriteHeader(404)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	ok, err := auth.CanAccessCollection(req.Context(), s.DB, r.Collection())
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		log.DebugR(req, "user can't access collection", log.Data{"collection_id": r.CollectionID})
+		w.WriteHeader(403)
+		return
+	}
+
+	o := newPublishedCollectionOutput(r)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
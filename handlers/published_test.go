@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+func TestNewPublishedCollectionOutput(t *testing.T) {
+	started := time.Date(2017, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	o := newPublishedCollectionOutput(model.PublishRecord{
+		ID:             "p1",
+		CollectionID:   "c1",
+		CollectionName: "March release",
+		Started:        started,
+		Completed:      started.Add(time.Second + time.Millisecond*250),
+		Files:          12,
+		PendingDeletes: 1,
+	})
+
+	if o.Duration != 1250 {
+		t.Errorf("Duration = %d, want 1250", o.Duration)
+	}
+	if o.Name != "March release" || o.Files != 12 || o.PendingDeletes != 1 {
+		t.Errorf("output = %+v", o)
+	}
+}
+
+func TestListPublishedCollectionsPaging(t *testing.T) {
+	s := &FloServer{}
+
+	for _, q := range []string{"page=0", "page=x", "size=0", "size=101", "size=x"} {
+		w := httptest.NewRecorder()
+		s.ListPublishedCollections(w, httptest.NewRequest("GET", "/publishedCollections?"+q, nil))
+
+		if w.Code != 400 {
+			t.Errorf("ListPublishedCollections(%q) = %d, want 400", q, w.Code)
+		}
+	}
+}
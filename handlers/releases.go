@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
+)
+
+type releaseOutput struct {
+	URI         string                    `json:"uri"`
+	Title       string                    `json:"title"`
+	ReleaseDate time.Time                 `json:"releaseDate"`
+	Status      string                    `json:"status"`
+	Provisional bool                      `json:"provisional"`
+	Confirmed   bool                      `json:"confirmed"`
+	Published   *time.Time                `json:"published,omitempty"`
+	Collections []releaseCollectionOutput `json:"collections"`
+}
+
+type releaseCollectionOutput struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	ApprovalStatus  string     `json:"approvalStatus"`
+	PublishDate     *time.Time `json:"publishDate,omitempty"`
+	PublishComplete bool       `json:"publishComplete"`
+}
+
+type createReleaseInput struct {
+	URI         string     `json:"uri"`
+	Title       string     `json:"title"`
+	ReleaseDate *time.Time `json:"releaseDate"`
+	Status      string     `json:"status"`
+	Provisional bool       `json:"provisional"`
+	Confirmed   bool       `json:"confirmed"`
+}
+
+type updateReleaseInput struct {
+	Title       *string    `json:"title"`
+	ReleaseDate *time.Time `json:"releaseDate"`
+	Status      *string    `json:"status"`
+	Provisional *bool      `json:"provisional"`
+	Confirmed   *bool      `json:"confirmed"`
+}
+
+// newReleaseOutput includes the collections linked to the release which
+// the user can access
+func (s *FloServer) newReleaseOutput(req *http.Request, r model.Release, cols []model.Collection) (releaseOutput, error) {
+	o := releaseOutput{
+		URI:         r.URI,
+		Title:       r.Title,
+		ReleaseDate: r.ReleaseDate,
+		Status:      r.Status,
+		Provisional: r.Provisional,
+		Confirmed:   r.Confirmed,
+		Published:   r.Published,
+		Collections: []releaseCollectionOutput{},
+	}
+
+	for _, c := range cols {
+		ok, err := auth.CanAccessCollection(req.Context(), s.DB, c)
+		if err != nil {
+			return o, err
+		}
+		if !ok {
+			continue
+		}
+
+		o.Collections = append(o.Collections, releaseCollectionOutput{
+			ID:              c.ID,
+			Name:            c.Name,
+			Type:            c.Type,
+			ApprovalStatus:  c.Approval(),
+			PublishDate:     c.PublishDate,
+			PublishComplete: c.Published,
+		})
+	}
+
+	return o, nil
+}
+
+func (s *FloServer) writeRelease(w http.ResponseWriter, req *http.Request, status int, r model.Release) {
+	cols, err := s.DB.ListCollectionsForReleases(r.URI)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o, err := s.newReleaseOutput(req, r, cols[r.URI])
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func writeReleaseError(w http.ResponseWriter, req *http.Request, err error) {
+	log.DebugR(req, "release error", log.Data{"error": err})
+	switch err {
+	case data.ErrReleaseNotFound:
+		w.WriteHeader(404)
+	case data.ErrReleaseExists, data.ErrReleaseClosed:
+		w.WriteHeader(409)
+	case data.ErrInvalidReleaseStatus:
+		w.WriteHeader(400)
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+	}
+}
+
+// ListReleases ...
+func (s *FloServer) ListReleases(w http.ResponseWriter, req *http.Request) {
+	releases, err := s.DB.GetReleases()
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	var uris []string
+	for _, r := range releases {
+		uris = append(uris, r.URI)
+	}
+
+	cols, err := s.DB.ListCollectionsForReleases(uris...)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o := []releaseOutput{}
+	for _, r := range releases {
+		rO, err := s.newReleaseOutput(req, r, cols[r.URI])
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+		o = append(o, rO)
+	}
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(b)
+}
+
+// GetRelease ...
+func (s *FloServer) GetRelease(w http.ResponseWriter, req *http.Request) {
+	r, err := s.DB.GetRelease(mux.Vars(req)["uri"])
+	if err != nil {
+		writeReleaseError(w, req, err)
+		return
+	}
+
+	s.writeRelease(w, req, 200, r)
+}
+
+// CreateRelease ...
+func (s *FloServer) CreateRelease(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input createReleaseInput
+	if err := unmarshal(req, &input); err != nil || len(input.URI) == 0 || len(input.Title) == 0 || input.ReleaseDate == nil {
+		log.DebugR(req, "invalid release", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	r := model.Release{
+		URI:         model.CleanURI(input.URI),
+		Title:       input.Title,
+		ReleaseDate: *input.ReleaseDate,
+		Status:      input.Status,
+		Provisional: input.Provisional,
+		Confirmed:   input.Confirmed,
+	}
+
+	if err := s.DB.CreateRelease(creator.ID.Hex(), r); err != nil {
+		writeReleaseError(w, req, err)
+		return
+	}
+
+	created, err := s.DB.GetRelease(r.URI)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	s.writeRelease(w, req, 201, created)
+}
+
+// UpdateRelease ...
+func (s *FloServer) UpdateRelease(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input updateReleaseInput
+	if err := unmarshal(req, &input); err != nil || (input.Title != nil && len(*input.Title) == 0) {
+		log.DebugR(req, "invalid release", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	r, err := s.DB.UpdateRelease(*creator, mux.Vars(req)["uri"], data.ReleaseUpdate{
+		Title:       input.Title,
+		ReleaseDate: input.ReleaseDate,
+		Status:      input.Status,
+		Provisional: input.Provisional,
+		Confirmed:   input.Confirmed,
+	})
+	if err != nil {
+		writeReleaseError(w, req, err)
+		return
+	}
+
+	s.writeRelease(w, req, 200, r)
+}
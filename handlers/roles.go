@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
+)
+
+type roleInput struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	Permissions map[string]permissionOutput `json:"permissions"`
+}
+
+func (r roleInput) toRole() model.Role {
+	role := model.Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Permissions: make(map[string]model.Permission),
+	}
+	for k, p := range r.Permissions {
+		role.Permissions[k] = model.Permission{
+			CollectionTypes: p.CollectionTypes,
+			ContentPaths:    p.ContentPaths,
+		}
+	}
+	return role
+}
+
+func newRoleOutput(role model.Role) roleOutput {
+	rO := roleOutput{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: make(map[string]permissionOutput),
+	}
+	for k, p := range role.Permissions {
+		rO.Permissions[k] = newPermissionOutput(p)
+	}
+	return rO
+}
+
+// ListRoles ...
+func (s *FloServer) ListRoles(w http.ResponseWriter, req *http.Request) {
+	roles, err := s.DB.GetRoles()
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	o := []roleOutput{}
+	for _, role := range roles {
+		o = append(o, newRoleOutput(role))
+	}
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// GetRole ...
+func (s *FloServer) GetRole(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["role_id"]
+
+	role, err := s.DB.GetRole(id)
+	if err != nil {
+		log.DebugR(req, "error fetching role", log.Data{"error": err})
+		if err == data.ErrRoleNotFound {
+			w.WriteHeader(404)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	o := newRoleOutput(role)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// CreateRole ...
+func (s *FloServer) CreateRole(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input roleInput
+	if err := unmarshal(req, &input); err != nil {
+		log.DebugR(req, "error reading body", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	if len(input.ID) == 0 || len(input.Name) == 0 {
+		log.DebugR(req, "role id and name are required", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	role := input.toRole()
+
+	err := s.DB.CreateRole(creator.ID.Hex(), role)
+	if err != nil {
+		log.DebugR(req, "error creating role", log.Data{"error": err})
+		if err == data.ErrRoleExists {
+			w.WriteHeader(409)
+			return
+		} else if err == data.ErrUnknownPermission || err == data.ErrInvalidCollectionType {
+			w.WriteHeader(400)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	o := newRoleOutput(role)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	w.Write(b)
+}
+
+// UpdateRole ...
+func (s *FloServer) UpdateRole(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input roleInput
+	if err := unmarshal(req, &input); err != nil {
+		log.DebugR(req, "error reading body", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	id := mux.Vars(req)["role_id"]
+	if len(input.ID) > 0 && input.ID != id {
+		log.DebugR(req, "role id in body does not match url", log.Data{"id": id, "body_id": input.ID})
+		w.WriteHeader(400)
+		return
+	}
+	input.ID = id
+
+	if len(input.Name) == 0 {
+		log.DebugR(req, "role name is required", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	role := input.toRole()
+
+	err := s.DB.UpdateRole(creator.ID.Hex(), role)
+	if err != nil {
+		log.DebugR(req, "error updating role", log.Data{"error": err})
+		if err == data.ErrRoleNotFound {
+			w.WriteHeader(404)
+			return
+		} else if err == data.ErrUnknownPermission || err == data.ErrInvalidCollectionType {
+			w.WriteHeader(400)
+			return
+		} else if err == data.ErrRoleProtected {
+			w.WriteHeader(409)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	o := newRoleOutput(role)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// DeleteRole ...
+func (s *FloServer) DeleteRole(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	id := mux.Vars(req)["role_id"]
+
+	err := s.DB.DeleteRole(creator.ID.Hex(), id)
+	if err != nil {
+		log.DebugR(req, "error deleting role", log.Data{"error": err})
+		if err == data.ErrRoleNotFound {
+			w.WriteHeader(404)
+			return
+		} else if err == data.ErrRoleInUse || err == data.ErrRoleProtected {
+			w.WriteHeader(409)
+			return
+		}
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(204)
+}
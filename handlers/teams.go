@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
 	"github.com/ONSdigital/go-ns/log"
+	"github.com/gorilla/mux"
 )
 
 type teamsOutput struct {
@@ -12,14 +16,78 @@ type teamsOutput struct {
 }
 
 type teamOutput struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+type teamInput struct {
+	Name string `json:"name"`
+}
+
+type teamMemberInput struct {
+	Email string `json:"email"`
+}
+
+func newTeamOutput(t model.Team) teamOutput {
+	o := teamOutput{
+		ID:      t.ID,
+		Name:    t.Name,
+		Members: t.Members,
+	}
+	if o.Members == nil {
+		o.Members = []string{}
+	}
+	return o
+}
+
+func writeTeam(w http.ResponseWriter, req *http.Request, status int, t model.Team) {
+	o := newTeamOutput(t)
+
+	b, err := json.Marshal(&o)
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func writeTeamError(w http.ResponseWriter, req *http.Request, err error) {
+	log.DebugR(req, "team error", log.Data{"error": err})
+	switch err {
+	case data.ErrTeamNotFound:
+		w.WriteHeader(404)
+	case data.ErrTeamExists:
+		w.WriteHeader(409)
+	case data.ErrUserNotFound:
+		w.WriteHeader(400)
+	default:
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+	}
 }
 
 // ListTeams ...
 func (s *FloServer) ListTeams(w http.ResponseWriter, req *http.Request) {
+	teams, err := s.DB.GetTeams()
+	if err != nil {
+		log.ErrorR(req, err, nil)
+		w.WriteHeader(500)
+		return
+	}
+
 	t := teamsOutput{
 		Teams: make([]teamOutput, 0),
 	}
 
+	for _, team := range teams {
+		t.Teams = append(t.Teams, newTeamOutput(team))
+	}
+
 	b, err := json.Marshal(&t)
 	if err != nil {
 		log.ErrorR(req, err, nil)
@@ -30,3 +98,149 @@ func (s *FloServer) ListTeams(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(b)
 }
+
+// GetTeam ...
+func (s *FloServer) GetTeam(w http.ResponseWriter, req *http.Request) {
+	t, err := s.DB.GetTeam(mux.Vars(req)["team_id"])
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	writeTeam(w, req, 200, t)
+}
+
+// CreateTeam ...
+func (s *FloServer) CreateTeam(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input teamInput
+	if err := unmarshal(req, &input); err != nil || len(input.Name) == 0 {
+		log.DebugR(req, "invalid team", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	t, err := s.DB.CreateTeam(creator.ID.Hex(), input.Name)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	writeTeam(w, req, 201, t)
+}
+
+// RenameTeam ...
+func (s *FloServer) RenameTeam(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input teamInput
+	if err := unmarshal(req, &input); err != nil || len(input.Name) == 0 {
+		log.DebugR(req, "invalid team", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	id := mux.Vars(req)["team_id"]
+
+	err := s.DB.RenameTeam(creator.ID.Hex(), id, input.Name)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	t, err := s.DB.GetTeam(id)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	writeTeam(w, req, 200, t)
+}
+
+// DeleteTeam ...
+func (s *FloServer) DeleteTeam(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	err := s.DB.DeleteTeam(creator.ID.Hex(), mux.Vars(req)["team_id"])
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// AddTeamMember ...
+func (s *FloServer) AddTeamMember(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	var input teamMemberInput
+	if err := unmarshal(req, &input); err != nil || len(input.Email) == 0 {
+		log.DebugR(req, "invalid team member", log.Data{"error": err})
+		w.WriteHeader(400)
+		return
+	}
+
+	id := mux.Vars(req)["team_id"]
+
+	err := s.DB.AddTeamMember(creator.ID.Hex(), id, input.Email)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	t, err := s.DB.GetTeam(id)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	writeTeam(w, req, 200, t)
+}
+
+// RemoveTeamMember ...
+func (s *FloServer) RemoveTeamMember(w http.ResponseWriter, req *http.Request) {
+	creator, ok := auth.UserFromContext(req.Context())
+	if !ok {
+		log.DebugR(req, "user not logged in", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	id := mux.Vars(req)["team_id"]
+
+	err := s.DB.RemoveTeamMember(creator.ID.Hex(), id, mux.Vars(req)["email"])
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	t, err := s.DB.GetTeam(id)
+	if err != nil {
+		writeTeamError(w, req, err)
+		return
+	}
+
+	writeTeam(w, req, 200, t)
+}
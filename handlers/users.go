@@ -3,18 +3,25 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/ONSdigital/dp-florence-api/auth"
 	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
 	"github.com/ONSdigital/go-ns/log"
 )
 
 type userOutput struct {
-	Name              string `json:"name"`
-	Email             string `json:"email"`
-	Inactive          bool   `json:"inactive"`
-	TemporaryPassword bool   `json:"temporaryPassword"`
-	LastAdmin         string `json:"lastAdmin"`
+	Name               string     `json:"name"`
+	Email              string     `json:"email"`
+	Inactive           bool       `json:"inactive"`
+	TemporaryPassword  bool       `json:"temporaryPassword"`
+	LastAdmin          string     `json:"lastAdmin"`
+	LastLogin          *time.Time `json:"lastLogin,omitempty"`
+	LastLoginIP        string     `json:"lastLoginIp,omitempty"`
+	LastPasswordChange *time.Time `json:"lastPasswordChange,omitempty"`
+	LastModified       *time.Time `json:"lastModified,omitempty"`
+	LastModifiedBy     string     `json:"lastModifiedBy,omitempty"`
 }
 
 type createUserInput struct {
@@ -29,6 +36,21 @@ type createUserInputPermissions struct {
 	DataVisPublisher bool `json:"dataVisPublisher"`
 }
 
+func newUserOutput(user model.User) userOutput {
+	return userOutput{
+		Name:               user.Name,
+		Email:              user.Email,
+		Inactive:           !user.Active,
+		TemporaryPassword:  user.ForcePasswordChange,
+		LastAdmin:          user.LastAdmin,
+		LastLogin:          user.LastLogin,
+		LastLoginIP:        user.LastLoginIP,
+		LastPasswordChange: user.LastPasswordChange,
+		LastModified:       user.LastModified,
+		LastModifiedBy:     user.LastModifiedBy,
+	}
+}
+
 // ListUsers ...
 func (s *FloServer) ListUsers(w http.ResponseWriter, req *http.Request) {
 	// FIXME this should be a different URL!
@@ -47,13 +69,7 @@ func (s *FloServer) ListUsers(w http.ResponseWriter, req *http.Request) {
 	}
 
 	for _, user := range users {
-		u = append(u, userOutput{
-			Name:              user.Name,
-			Email:             user.Email,
-			Inactive:          !user.Active,
-			TemporaryPassword: user.ForcePasswordChange,
-			LastAdmin:         "", // FIXME this seems useless?
-		})
+		u = append(u, newUserOutput(user))
 	}
 
 	b, err := json.Marshal(&u)
@@ -83,13 +99,7 @@ func (s *FloServer) GetUser(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	u := userOutput{
-		Name:              user.Name,
-		Email:             user.Email,
-		Inactive:          !user.Active,
-		TemporaryPassword: user.ForcePasswordChange,
-		LastAdmin:         "", // FIXME this seems useless?
-	}
+	u := newUserOutput(user)
 
 	b, err := json.Marshal(&u)
 	if err != nil {
@@ -118,7 +128,7 @@ func (s *FloServer) CreateUser(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := s.DB.CreateUser(creator.ID.Hex(), input.Email, input.Name)
+	err := s.DB.CreateUser(*creator, input.Email, input.Name)
 	if err != nil {
 		log.ErrorR(req, err, nil)
 		if err == data.ErrUserExists {
@@ -132,14 +142,16 @@ func (s *FloServer) CreateUser(w http.ResponseWriter, req *http.Request) {
 	var roles []string
 
 	if input.Permissions.Admin {
-		roles = append(roles, "administrator")
+		roles = append(roles, model.RoleAdministrator)
 	}
 	if input.Permissions.Editor {
-		roles = append(roles, "editor")
+		roles = append(roles, model.RoleEditor)
+	}
+	if input.Permissions.DataVisPublisher {
+		roles = append(roles, model.RoleDataVisPublisher)
 	}
-	// FIXME handle data vis users
 
-	err = s.DB.SetUserRoles(creator.ID.Hex(), input.Email, roles...)
+	err = s.DB.SetUserRoles(*creator, input.Email, roles...)
 	if err != nil {
 		log.ErrorR(req, err, nil)
 		if err == data.ErrUserExists {
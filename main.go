@@ -2,17 +2,22 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"expvar"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/ONSdigital/dp-florence-api/auth"
+	"github.com/ONSdigital/dp-florence-api/content"
 	"github.com/ONSdigital/dp-florence-api/data"
 	"github.com/ONSdigital/dp-florence-api/data/model"
 	"github.com/ONSdigital/dp-florence-api/handlers"
+	"github.com/ONSdigital/dp-florence-api/publish"
 	"github.com/ONSdigital/go-ns/log"
 	"github.com/ONSdigital/go-ns/server"
 	"github.com/gorilla/mux"
@@ -23,6 +28,18 @@ func main() {
 	bindAddr := ":8082"
 	mongoURI := "mongodb://localhost:27017"
 	initDB := false
+	roleCacheTTL := data.DefaultRoleCacheTTL
+	schedulerInterval := publish.DefaultSchedulerInterval
+	collectionsDir := "collections"
+	receiverDir := ""
+	receiverAddr := ":8083"
+	trustedProxies := ""
+	contentDir := ""
+	publishConfig := publish.Config{
+		Target:            "filesystem",
+		WebsiteContentDir: "website",
+		BundleDir:         "bundles",
+	}
 
 	if v := os.Getenv("BIND_ADDR"); len(v) > 0 {
 		bindAddr = v
@@ -36,20 +53,140 @@ func main() {
 		initDB, _ = strconv.ParseBool(v)
 	}
 
+	if v := os.Getenv("TRUSTED_PROXIES"); len(v) > 0 {
+		trustedProxies = v
+	}
+
+	if v := os.Getenv("ROLE_CACHE_TTL"); len(v) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Error(err, nil)
+			os.Exit(1)
+		}
+		roleCacheTTL = d
+	}
+
+	if v := os.Getenv("SCHEDULER_INTERVAL"); len(v) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Error(err, nil)
+			os.Exit(1)
+		}
+		schedulerInterval = d
+	}
+
+	if v := os.Getenv("COLLECTIONS_DIR"); len(v) > 0 {
+		collectionsDir = v
+	}
+
+	if v := os.Getenv("CONTENT_DIR"); len(v) > 0 {
+		contentDir = v
+	}
+
+	if v := os.Getenv("PUBLISH_TARGET"); len(v) > 0 {
+		publishConfig.Target = v
+	}
+
+	if v := os.Getenv("WEBSITE_CONTENT_DIR"); len(v) > 0 {
+		publishConfig.WebsiteContentDir = v
+	}
+
+	if v := os.Getenv("PUBLISH_RECEIVER_URL"); len(v) > 0 {
+		publishConfig.ReceiverURL = v
+	}
+
+	if v := os.Getenv("PUBLISH_RECEIVER_DIR"); len(v) > 0 {
+		receiverDir = v
+	}
+
+	if v := os.Getenv("PUBLISH_RECEIVER_ADDR"); len(v) > 0 {
+		receiverAddr = v
+	}
+
+	if v := os.Getenv("PUBLISH_RECEIVER_KEY"); len(v) > 0 {
+		publishConfig.ReceiverKey = []byte(v)
+	}
+
+	if v := os.Getenv("PUBLISH_BUNDLE_DIR"); len(v) > 0 {
+		publishConfig.BundleDir = v
+	}
+
+	if v := os.Getenv("PUBLISH_BUNDLE_KEY"); len(v) > 0 {
+		publishConfig.BundleKey = []byte(v)
+	}
+
 	mongoDB, err := data.NewMongoDB(mongoURI)
 	if err != nil {
 		log.Error(err, nil)
 		os.Exit(1)
 	}
 
+	mongoDB.SetRoleCacheTTL(roleCacheTTL)
+
 	if initDB {
 		initTest(mongoDB)
 	}
 
-	floServer := &handlers.FloServer{DB: mongoDB}
+	if err = mongoDB.EnsureDefaultRoles(); err != nil {
+		log.Error(err, nil)
+		os.Exit(1)
+	}
+
+	if err = mongoDB.MigrateRolePermissions(); err != nil {
+		log.Error(err, nil)
+		os.Exit(1)
+	}
+
+	// published content is read from the filesystem target's current
+	// release unless another content directory is given. Other targets don't
+	// publish anywhere this service can read, so it has to be given for them.
+	if len(contentDir) == 0 {
+		if publishConfig.Target != "filesystem" {
+			log.Error(errors.New("CONTENT_DIR must be set for the publish target"), log.Data{"target": publishConfig.Target})
+			os.Exit(1)
+		}
+		contentDir = filepath.Join(publishConfig.WebsiteContentDir, "current")
+	}
+	contentStore := content.NewFilesystemStore(contentDir)
+
+	target, err := publish.NewTarget(publishConfig)
+	if err != nil {
+		log.Error(err, log.Data{"target": publishConfig.Target})
+		os.Exit(1)
+	}
+
+	publisher, err := publish.New(mongoDB, publish.TargetStep(collectionsDir, target))
+	if err != nil {
+		log.Error(err, nil)
+		os.Exit(1)
+	}
+
+	// the filesystem target can only be written by one publisher at a time
+	if t, ok := target.(*publish.FilesystemTarget); ok {
+		publisher.TargetLease = t.LeaseName()
+	}
+	publisher.Checks = append(publisher.Checks,
+		publish.PendingDeleteCheck(mongoDB, contentStore),
+		publish.BrokenLinkCheck(collectionsDir, contentStore),
+	)
+
+	scheduler := publish.NewScheduler(publisher, schedulerInterval)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	proxies, err := handlers.ParseTrustedProxies(trustedProxies)
+	if err != nil {
+		log.Error(err, log.Data{"trusted_proxies": trustedProxies})
+		os.Exit(1)
+	}
+
+	floServer := &handlers.FloServer{DB: mongoDB, Publisher: publisher, Content: contentStore, CollectionsDir: collectionsDir, TrustedProxies: proxies}
 	authMw := auth.Middleware(mongoDB, true)
 	//authMwMaybe := auth.Middleware(mongoDB, false)
-	adminMw := auth.WithPermission(mongoDB, model.PermAdministrator)
+	permMw := func(perm string) func(h http.HandlerFunc) http.Handler {
+		return auth.WithPermission(mongoDB, perm)
+	}
+	colMw := auth.WithCollection(mongoDB)
 
 	router := mux.NewRouter()
 	srv := server.New(bindAddr, router)
@@ -61,18 +198,52 @@ func main() {
 	root.Methods("POST").Path("/login").HandlerFunc(floServer.Login)
 	root.Methods("POST").Path("/password").HandlerFunc(floServer.ChangePassword)
 
-	root.Methods("GET").Path("/master/{uri:.*}").Handler(authMw(floServer.MasterData))
+	root.Methods("GET").Path("/me").Handler(authMw(floServer.Me))
+
+	root.Methods("GET", "HEAD").Path("/master/{uri:.*}").Handler(permMw(model.PermContentRead)(floServer.MasterData))
+
+	root.Methods("GET").Path("/publishedCollections").Handler(permMw(model.PermCollectionsRead)(floServer.ListPublishedCollections))
+	root.Methods("GET").Path("/publishedCollections/{publish_id}").Handler(permMw(model.PermCollectionsRead)(floServer.GetPublishedCollection))
+	root.Methods("GET").Path("/collections").Handler(permMw(model.PermCollectionsRead)(floServer.ListCollections))
+	root.Methods("POST").Path("/collections").Handler(permMw(model.PermCollectionsCreate)(floServer.CreateCollection))
+	root.Methods("GET").Path("/collections/{collection_id}/browse-tree").Handler(permMw(model.PermCollectionsRead)(colMw(floServer.GetCollectionBrowseTree)))
+	root.Methods("GET").Path("/collections/{collection_id}").Handler(permMw(model.PermCollectionsRead)(colMw(floServer.GetCollection)))
+	root.Methods("PUT").Path("/collections/{collection_id}").Handler(permMw(model.PermCollectionsWrite)(colMw(floServer.UpdateCollection)))
+	root.Methods("DELETE").Path("/collections/{collection_id}").Handler(permMw(model.PermCollectionsWrite)(colMw(floServer.DeleteCollection)))
+	root.Methods("POST").Path("/collections/{collection_id}/approve").Handler(permMw(model.PermCollectionsApprove)(colMw(floServer.ApproveCollection)))
+	root.Methods("POST").Path("/collections/{collection_id}/publish").Handler(permMw(model.PermCollectionsPublish)(colMw(floServer.PublishCollection)))
+	root.Methods("POST").Path("/collections/{collection_id}/unlock").Handler(permMw(model.PermCollectionsApprove)(colMw(floServer.UnlockCollection)))
+	root.Methods("POST").Path("/collections/{collection_id}/edit").Handler(permMw(model.PermContentWrite)(colMw(floServer.EditContent)))
+	root.Methods("POST").Path("/collections/{collection_id}/complete").Handler(permMw(model.PermContentWrite)(colMw(floServer.CompleteContent)))
+	root.Methods("POST").Path("/collections/{collection_id}/review").Handler(permMw(model.PermContentWrite)(colMw(floServer.ReviewContent)))
+	root.Methods("GET", "HEAD").Path("/collections/{collection_id}/content/{uri:.*}").Handler(permMw(model.PermContentRead)(colMw(floServer.GetCollectionContent)))
+	root.Methods("PUT").Path("/collections/{collection_id}/content/{uri:.*}").Handler(permMw(model.PermContentWrite)(colMw(floServer.PutCollectionContent)))
+	root.Methods("DELETE").Path("/collections/{collection_id}/content/{uri:.*}").Handler(permMw(model.PermContentWrite)(colMw(floServer.DeleteCollectionContent)))
+	root.Methods("POST").Path("/collections/{collection_id}/deletes").Handler(permMw(model.PermContentWrite)(colMw(floServer.AddPendingDelete)))
+	root.Methods("DELETE").Path("/collections/{collection_id}/deletes").Handler(permMw(model.PermContentWrite)(colMw(floServer.CancelPendingDelete)))
+	root.Methods("POST").Path("/collections/{collection_id}/teams").Handler(permMw(model.PermCollectionsWrite)(colMw(floServer.AddCollectionTeam)))
+	root.Methods("DELETE").Path("/collections/{collection_id}/teams/{team_id}").Handler(permMw(model.PermCollectionsWrite)(colMw(floServer.RemoveCollectionTeam)))
+	root.Methods("GET").Path("/users").Handler(permMw(model.PermUsersRead)(floServer.ListUsers))
+	root.Methods("POST").Path("/users").Handler(permMw(model.PermUsersWrite)(floServer.CreateUser))
+	root.Methods("GET").Path("/teams").Handler(permMw(model.PermTeamsRead)(floServer.ListTeams))
+	root.Methods("POST").Path("/teams").Handler(permMw(model.PermTeamsWrite)(floServer.CreateTeam))
+	root.Methods("GET").Path("/teams/{team_id}").Handler(permMw(model.PermTeamsRead)(floServer.GetTeam))
+	root.Methods("PUT").Path("/teams/{team_id}").Handler(permMw(model.PermTeamsWrite)(floServer.RenameTeam))
+	root.Methods("DELETE").Path("/teams/{team_id}").Handler(permMw(model.PermTeamsWrite)(floServer.DeleteTeam))
+	root.Methods("POST").Path("/teams/{team_id}/members").Handler(permMw(model.PermTeamsWrite)(floServer.AddTeamMember))
+	root.Methods("DELETE").Path("/teams/{team_id}/members/{email}").Handler(permMw(model.PermTeamsWrite)(floServer.RemoveTeamMember))
+	root.Methods("GET").Path("/permission").Handler(permMw(model.PermUsersRead)(floServer.GetPermissions))
+	root.Methods("POST").Path("/permission").Handler(permMw(model.PermUsersWrite)(floServer.UpdatePermissions))
+	root.Methods("GET").Path("/roles").Handler(permMw(model.PermRolesRead)(floServer.ListRoles))
+	root.Methods("POST").Path("/roles").Handler(permMw(model.PermRolesWrite)(floServer.CreateRole))
+	root.Methods("GET").Path("/roles/{role_id}").Handler(permMw(model.PermRolesRead)(floServer.GetRole))
+	root.Methods("PUT").Path("/roles/{role_id}").Handler(permMw(model.PermRolesWrite)(floServer.UpdateRole))
+	root.Methods("DELETE").Path("/roles/{role_id}").Handler(permMw(model.PermRolesWrite)(floServer.DeleteRole))
 
-	root.Methods("GET").Path("/publishedCollections").Handler(authMw(floServer.ListPublishedCollections))
-	root.Methods("GET").Path("/collections").Handler(authMw(floServer.ListCollections))
-	root.Methods("POST").Path("/collections").Handler(authMw(floServer.CreateCollection))
-	root.Methods("GET").Path("/collections/{collection_id}/browse-tree").Handler(authMw(floServer.GetCollectionBrowseTree))
-	root.Methods("GET").Path("/collections/{collection_id}").Handler(authMw(floServer.GetCollection))
-	root.Methods("GET").Path("/users").Handler(authMw(floServer.ListUsers))
-	root.Methods("POST").Path("/users").Handler(adminMw(floServer.CreateUser))
-	root.Methods("GET").Path("/teams").Handler(authMw(floServer.ListTeams))
-	root.Methods("GET").Path("/permission").Handler(authMw(floServer.GetPermissions))
-	root.Methods("POST").Path("/permission").Handler(adminMw(floServer.UpdatePermissions))
+	root.Methods("GET").Path("/releases").Handler(permMw(model.PermReleasesRead)(floServer.ListReleases))
+	root.Methods("POST").Path("/releases").Handler(permMw(model.PermReleasesWrite)(floServer.CreateRelease))
+	root.Methods("GET").Path("/releases/{uri:.+}").Handler(permMw(model.PermReleasesRead)(floServer.GetRelease))
+	root.Methods("PUT").Path("/releases/{uri:.+}").Handler(permMw(model.PermReleasesWrite)(floServer.UpdateRelease))
 
 	root.Methods("POST").Path("/ping").HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		type pingResponse struct {
@@ -89,7 +260,7 @@ func main() {
 		_, tok, err := mongoDB.LoadUserFromToken(t)
 		if err == nil {
 			pR.HasSession = true
-			expiry := tok.LastActive.Add(time.Minute * 60)
+			expiry := tok.LastActive.Add(auth.SessionTimeout)
 			pR.ExpiryDate = &expiry
 		}
 
@@ -108,6 +279,8 @@ func main() {
 	// root.Methods("GET").Path("/data").Handler(authMw(func(w http.ResponseWriter, req *http.Request) {}))
 	// root.Methods("GET").Path("/taxonomy").Handler(authMw(func(w http.ResponseWriter, req *http.Request) {}))
 
+	root.Methods("GET").Path("/metrics").Handler(permMw(model.PermAuditRead)(expvar.Handler().ServeHTTP))
+
 	root.Methods("POST").Path("/clickEventLog").HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		// TODO ?
 	})
@@ -116,6 +289,25 @@ func main() {
 		w.WriteHeader(404)
 	})
 
+	// the publish receiver writes straight to disk, so it's kept off the
+	// main router and only accepts requests carrying the receiver key
+	if len(receiverDir) > 0 {
+		if len(publishConfig.ReceiverKey) == 0 {
+			log.Error(publish.ErrNoReceiverKey, nil)
+			os.Exit(1)
+		}
+
+		receiverSrv := server.New(receiverAddr, &publish.Receiver{Dir: receiverDir, Key: publishConfig.ReceiverKey})
+
+		go func() {
+			log.Debug("starting publish receiver", log.Data{"bind_addr": receiverAddr})
+			if err := receiverSrv.ListenAndServe(); err != nil {
+				log.Error(err, nil)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	log.Debug("starting http server", log.Data{"bind_addr": bindAddr})
 	if err := srv.ListenAndServe(); err != nil {
 		log.Error(err, nil)
@@ -132,31 +324,14 @@ func initTest(db *data.MongoDB) {
 		panic(err)
 	}
 
-	r := model.Role{
-		ID:   "administrator",
-		Name: "Administrator",
-		Permissions: map[string]model.Permission{
-			model.PermAdministrator: model.Permission{},
-		},
-	}
-	_, err = sess.DB("florence").C("roles").Upsert(bson.M{"_id": "administrator"}, r)
-	if err != nil {
-		panic(err)
-	}
-
-	r = model.Role{
-		ID:   "editor",
-		Name: "Editor",
-		Permissions: map[string]model.Permission{
-			model.PermEditor: model.Permission{},
-		},
-	}
-	_, err = sess.DB("florence").C("roles").Upsert(bson.M{"_id": "editor"}, r)
-	if err != nil {
-		panic(err)
+	for _, r := range model.DefaultRoles {
+		_, err = sess.DB("florence").C("roles").Upsert(bson.M{"_id": r.ID}, r)
+		if err != nil {
+			panic(err)
+		}
 	}
 
-	u := model.User{Email: "florence@magicroundabout.ons.gov.uk", Name: "Florence", Password: b, Created: time.Now(), Active: true, ForcePasswordChange: true, Roles: []string{"administrator", "editor"}}
+	u := model.User{Email: "florence@magicroundabout.ons.gov.uk", Name: "Florence", Password: b, Created: time.Now(), Active: true, ForcePasswordChange: true, Roles: []string{model.RoleAdministrator, model.RoleEditor}}
 	_, err = sess.DB("florence").C("users").Upsert(bson.M{"email": "florence@magicroundabout.ons.gov.uk"}, u)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,162 @@
+package publish
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// ErrNoBundleKey ...
+var ErrNoBundleKey = errors.New("bundle signing key not configured")
+
+// BundleTarget publishes by writing a signed tar.gz bundle of the collection
+// for another system to pick up.
+//
+// Each bundle contains the collection's files under their URIs and a
+// manifest.json. The bundle is signed with HMAC-SHA256 and the hex signature
+// written alongside it with a .sig suffix. The signature is written last, so
+// a bundle is complete once its .sig file exists.
+type BundleTarget struct {
+	Dir string
+	Key []byte
+}
+
+// BundleManifest describes the contents of a bundle
+type BundleManifest struct {
+	CollectionID string    `json:"collectionId"`
+	Name         string    `json:"name"`
+	Created      time.Time `json:"created"`
+	Files        []string  `json:"files"`
+	Deletes      []string  `json:"deletes"`
+}
+
+// NewBundleTarget ...
+func NewBundleTarget(dir string, key []byte) (*BundleTarget, error) {
+	if len(key) == 0 {
+		return nil, ErrNoBundleKey
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &BundleTarget{Dir: dir, Key: key}, nil
+}
+
+// Publish ...
+func (t *BundleTarget) Publish(ctx context.Context, c model.Collection, files []File, deletes []string) error {
+	name := filepath.Join(t.Dir, c.ID+"-"+strconv.FormatInt(time.Now().Unix(), 10)+".tar.gz")
+
+	tmp, err := ioutil.TempFile(t.Dir, ".bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	mac := hmac.New(sha256.New, t.Key)
+	if err = t.write(ctx, io.MultiWriter(tmp, mac), c, files, deletes); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(name+".sig", []byte(hex.EncodeToString(mac.Sum(nil))), 0644)
+}
+
+func (t *BundleTarget) write(ctx context.Context, w io.Writer, c model.Collection, files []File, deletes []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	m := BundleManifest{
+		CollectionID: c.ID,
+		Name:         c.Name,
+		Created:      time.Now(),
+		Files:        make([]string, 0, len(files)),
+		Deletes:      append([]string{}, deletes...),
+	}
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addBundleFile(tw, f); err != nil {
+			return err
+		}
+		m.Files = append(m.Files, f.URI)
+	}
+
+	b, err := json.Marshal(&m)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    "manifest.json",
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: m.Created,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = tw.Write(b); err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+func addBundleFile(tw *tar.Writer, f File) error {
+	in, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    "content/" + strings.TrimPrefix(model.CleanURI(f.URI), "/"),
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, in)
+	return err
+}
@@ -0,0 +1,127 @@
+package publish
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+func TestBundleTarget(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json":          "{}",
+		"/economy/inflation/cpi.xlsx": "cpi",
+	})
+
+	key := []byte("secret")
+	target, err := NewBundleTarget(filepath.Join(dir, "bundles"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := model.Collection{ID: "abc", Name: "Inflation"}
+	if err = target.Publish(context.Background(), c, files, []string{"/business"}); err != nil {
+		t.Fatal(err)
+	}
+
+	bundles, err := filepath.Glob(filepath.Join(dir, "bundles", "abc-*.tar.gz"))
+	if err != nil || len(bundles) != 1 {
+		t.Fatalf("bundles = %v, %v", bundles, err)
+	}
+
+	b, err := ioutil.ReadFile(bundles[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := ioutil.ReadFile(bundles[0] + ".sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	if want := hex.EncodeToString(mac.Sum(nil)); string(sig) != want {
+		t.Errorf("signature = %s, want %s", sig, want)
+	}
+
+	gz, err := gzip.NewReader(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	contents := make(map[string]string)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[h.Name] = string(body)
+	}
+
+	if contents["content/economy/data.json"] != "{}" || contents["content/economy/inflation/cpi.xlsx"] != "cpi" {
+		t.Errorf("bundle contents = %v", contents)
+	}
+
+	var m BundleManifest
+	if err = json.Unmarshal([]byte(contents["manifest.json"]), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.CollectionID != "abc" || m.Name != "Inflation" || len(m.Files) != 2 || !reflect.DeepEqual(m.Deletes, []string{"/business"}) {
+		t.Errorf("manifest = %+v", m)
+	}
+}
+
+func TestBundleTargetCancelled(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if _, err := NewBundleTarget(filepath.Join(dir, "bundles"), nil); err != ErrNoBundleKey {
+		t.Errorf("NewBundleTarget without a key = %v, want ErrNoBundleKey", err)
+	}
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json": "{}",
+	})
+
+	target, err := NewBundleTarget(filepath.Join(dir, "bundles"), []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err = target.Publish(ctx, model.Collection{ID: "abc"}, files, nil); err == nil {
+		t.Error("Publish with a cancelled context succeeded")
+	}
+
+	left, _ := ioutil.ReadDir(filepath.Join(dir, "bundles"))
+	if len(left) > 0 {
+		t.Errorf("files left in the bundle directory: %v", left)
+	}
+}
@@ -0,0 +1,246 @@
+package publish
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// CheckFailure describes a reason a collection can't be published
+type CheckFailure struct {
+	Check   string `json:"check"`
+	URI     string `json:"uri,omitempty"`
+	Message string `json:"message"`
+}
+
+// CheckError is returned by Publish when pre-publish checks fail
+type CheckError struct {
+	Failures []CheckFailure
+}
+
+func (e *CheckError) Error() string {
+	return "collection failed pre-publish checks"
+}
+
+// Check inspects a collection before it's published
+type Check func(c model.Collection) ([]CheckFailure, error)
+
+// RunChecks runs each check against the collection, returning a CheckError
+// if any of them fail
+func RunChecks(c model.Collection, checks ...Check) error {
+	var failures []CheckFailure
+
+	for _, check := range checks {
+		f, err := check(c)
+		if err != nil {
+			return err
+		}
+		failures = append(failures, f...)
+	}
+
+	if len(failures) > 0 {
+		return &CheckError{failures}
+	}
+
+	return nil
+}
+
+// ContentReviewedCheck fails for any content in the collection which hasn't
+// been reviewed
+func ContentReviewedCheck(db *data.MongoDB) Check {
+	return func(c model.Collection) ([]CheckFailure, error) {
+		content, err := db.GetCollectionContent(c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var failures []CheckFailure
+		for _, i := range content[c.ID] {
+			if i.State != model.ContentStateReviewed {
+				failures = append(failures, CheckFailure{
+					Check:   "content_reviewed",
+					URI:     i.URI,
+					Message: "content is " + i.State,
+				})
+			}
+		}
+
+		return failures, nil
+	}
+}
+
+// PendingDeleteCheck fails if content in the collection is also marked for
+// deletion, or if pages have been published below a pending delete since it
+// was marked, as they would be deleted without having been reviewed
+func PendingDeleteCheck(db *data.MongoDB, published content.Reader) Check {
+	return func(c model.Collection) ([]CheckFailure, error) {
+		if len(c.PendingDeletes) == 0 {
+			return nil, nil
+		}
+
+		items, err := db.GetCollectionContent(c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var failures []CheckFailure
+		for _, i := range items[c.ID] {
+			if d, ok := c.PendingDelete(i.URI); ok {
+				failures = append(failures, CheckFailure{
+					Check:   "pending_delete",
+					URI:     i.URI,
+					Message: "content is edited in the collection and marked for deletion under " + d.URI,
+				})
+			}
+		}
+
+		for _, d := range c.PendingDeletes {
+			tree, err := published.Tree(d.URI, -1)
+			if err != nil {
+				if err == content.ErrNotFound {
+					continue
+				}
+				return nil, err
+			}
+
+			marked := make(map[string]bool)
+			for _, uri := range d.Snapshot.URIs() {
+				marked[uri] = true
+			}
+
+			for _, uri := range tree.URIs() {
+				if !marked[uri] {
+					failures = append(failures, CheckFailure{
+						Check:   "pending_delete",
+						URI:     uri,
+						Message: "content was published after " + d.URI + " was marked for deletion",
+					})
+				}
+			}
+		}
+
+		return failures, nil
+	}
+}
+
+// BrokenLinkCheck fails for internal links in the collection's pages which
+// don't resolve to content in the collection or to published content, or
+// which link to content marked for deletion
+func BrokenLinkCheck(collectionsDir string, published content.Reader) Check {
+	return func(c model.Collection) ([]CheckFailure, error) {
+		files, err := CollectionFiles(collectionsDir, c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		working := content.NewFilesystemStore(CollectionDir(collectionsDir, c.ID))
+
+		var failures []CheckFailure
+		for _, f := range files {
+			if path.Base(f.URI) != content.DataFile {
+				continue
+			}
+
+			b, err := ioutil.ReadFile(f.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			var page interface{}
+			if err = json.Unmarshal(b, &page); err != nil {
+				failures = append(failures, CheckFailure{
+					Check:   "broken_links",
+					URI:     f.URI,
+					Message: "page content isn't valid json",
+				})
+				continue
+			}
+
+			links := internalLinks(page, true, map[string]bool{})
+			sort.Strings(links)
+
+			for _, link := range links {
+				if d, ok := c.PendingDelete(link); ok {
+					failures = append(failures, CheckFailure{
+						Check:   "broken_links",
+						URI:     f.URI,
+						Message: "links to " + link + " which is marked for deletion under " + d.URI,
+					})
+					continue
+				}
+
+				ok, err := contentExists(link, working, published)
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					failures = append(failures, CheckFailure{
+						Check:   "broken_links",
+						URI:     f.URI,
+						Message: "links to missing content " + link,
+					})
+				}
+			}
+		}
+
+		return failures, nil
+	}
+}
+
+// internalLinks returns the uri values in page JSON which link to other
+// content on the site, ignoring the page's own uri
+func internalLinks(v interface{}, top bool, seen map[string]bool) []string {
+	var links []string
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if s, ok := e.(string); ok && k == "uri" && !top {
+				if link := internalLink(s); len(link) > 0 && !seen[link] {
+					seen[link] = true
+					links = append(links, link)
+				}
+				continue
+			}
+			links = append(links, internalLinks(e, false, seen)...)
+		}
+	case []interface{}:
+		for _, e := range v {
+			links = append(links, internalLinks(e, false, seen)...)
+		}
+	}
+
+	return links
+}
+
+// internalLink returns the content URI for a link to another page on the
+// site, or an empty string for external links
+func internalLink(s string) string {
+	if !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") {
+		return ""
+	}
+
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+
+	return model.CleanURI(s)
+}
+
+func contentExists(uri string, readers ...content.Reader) (bool, error) {
+	for _, r := range readers {
+		ok, err := r.Exists(uri)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
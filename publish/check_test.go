@@ -0,0 +1,181 @@
+package publish
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/content"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+func TestInternalLink(t *testing.T) {
+	tests := []struct {
+		link, want string
+	}{
+		{"/economy/inflation", "/economy/inflation"},
+		{"/economy/inflation/", "/economy/inflation"},
+		{"/economy/inflation?page=2", "/economy/inflation"},
+		{"/economy/inflation#section", "/economy/inflation"},
+		{"https://www.ons.gov.uk/economy", ""},
+		{"//www.ons.gov.uk/economy", ""},
+		{"mailto:someone@ons.gov.uk", ""},
+		{"economy", ""},
+	}
+
+	for _, tt := range tests {
+		if got := internalLink(tt.link); got != tt.want {
+			t.Errorf("internalLink(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestInternalLinks(t *testing.T) {
+	var page interface{}
+	err := json.Unmarshal([]byte(`{
+		"uri": "/economy",
+		"description": {"title": "Economy"},
+		"sections": [
+			{"uri": "/economy/inflation"},
+			{"uri": "/economy/growth?page=1"},
+			{"uri": "https://example.com"}
+		],
+		"related": {"uri": "/economy/inflation", "nested": [{"uri": "/business"}]}
+	}`), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links := internalLinks(page, true, map[string]bool{})
+	sort.Strings(links)
+
+	want := []string{"/business", "/economy/growth", "/economy/inflation"}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("internalLinks = %v, want %v", links, want)
+	}
+}
+
+func TestCollectionFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	writeCollection(t, filepath.Join(dir, "abc"), map[string]string{
+		"/economy/data.json":    "{}",
+		"/economy/chart.png":    "png",
+		"/economy/.data.json.1": "partial",
+		"/.hidden/data.json":    "{}",
+	})
+
+	files, err := CollectionFiles(dir, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var uris []string
+	for _, f := range files {
+		uris = append(uris, f.URI)
+	}
+	sort.Strings(uris)
+
+	want := []string{"/economy/chart.png", "/economy/data.json"}
+	if !reflect.DeepEqual(uris, want) {
+		t.Errorf("CollectionFiles = %v, want %v", uris, want)
+	}
+
+	if files, err = CollectionFiles(dir, "missing"); err != nil || len(files) != 0 {
+		t.Errorf("CollectionFiles for a missing collection = %v, %v", files, err)
+	}
+}
+
+func TestBrokenLinkCheck(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	writeCollection(t, filepath.Join(dir, "published"), map[string]string{
+		"/economy/data.json":           "{}",
+		"/economy/inflation/data.json": "{}",
+	})
+
+	writeCollection(t, filepath.Join(dir, "collections", "abc"), map[string]string{
+		"/economy/growth/data.json": `{"uri": "/economy/growth", "links": [
+			{"uri": "/economy"},
+			{"uri": "/economy/growth/gdp"},
+			{"uri": "/economy/inflation/cpi"},
+			{"uri": "/economy/inflation"}
+		]}`,
+		"/economy/growth/gdp/data.json": `{"uri": "/economy/growth/gdp"}`,
+		"/business/data.json":           `not json`,
+	})
+
+	c := model.Collection{
+		ID:             "abc",
+		PendingDeletes: []model.PendingDelete{{URI: "/economy/inflation"}},
+	}
+
+	check := BrokenLinkCheck(filepath.Join(dir, "collections"), content.NewFilesystemStore(filepath.Join(dir, "published")))
+
+	failures, err := check(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CheckFailure{
+		{Check: "broken_links", URI: "/business/data.json", Message: "page content isn't valid json"},
+		{Check: "broken_links", URI: "/economy/growth/data.json", Message: "links to /economy/inflation which is marked for deletion under /economy/inflation"},
+		{Check: "broken_links", URI: "/economy/growth/data.json", Message: "links to /economy/inflation/cpi which is marked for deletion under /economy/inflation"},
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].URI+failures[i].Message < failures[j].URI+failures[j].Message
+	})
+
+	if !reflect.DeepEqual(failures, want) {
+		t.Errorf("failures = %+v, want %+v", failures, want)
+	}
+
+	c.PendingDeletes = nil
+
+	if failures, err = check(c); err != nil {
+		t.Fatal(err)
+	}
+
+	want = []CheckFailure{
+		{Check: "broken_links", URI: "/business/data.json", Message: "page content isn't valid json"},
+		{Check: "broken_links", URI: "/economy/growth/data.json", Message: "links to missing content /economy/inflation/cpi"},
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].URI+failures[i].Message < failures[j].URI+failures[j].Message
+	})
+
+	if !reflect.DeepEqual(failures, want) {
+		t.Errorf("failures = %+v, want %+v", failures, want)
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "publish-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeCollection creates a collection working directory holding files,
+// keyed by URI, and returns the files to publish
+func writeCollection(t *testing.T, dir string, files map[string]string) []File {
+	var r []File
+	for uri, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(uri))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		r = append(r, File{URI: uri, Path: p})
+	}
+	return r
+}
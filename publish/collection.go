@@ -0,0 +1,62 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// File is a single file being published from a collection
+type File struct {
+	// URI is the path of the file on the website
+	URI string
+	// Path is the location of the file on disk
+	Path string
+}
+
+// CollectionDir returns the working directory for a collection's content
+func CollectionDir(collectionsDir, collectionID string) string {
+	return filepath.Join(collectionsDir, collectionID)
+}
+
+// CollectionFiles lists every file in a collection's working directory,
+// ignoring hidden files and directories
+func CollectionFiles(collectionsDir, collectionID string) ([]File, error) {
+	root := CollectionDir(collectionsDir, collectionID)
+
+	var files []File
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, File{URI: model.CleanURI(filepath.ToSlash(rel)), Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
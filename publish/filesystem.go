@@ -0,0 +1,216 @@
+package publish
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// FilesystemTarget publishes into a website content directory.
+//
+// Content is served from Root/current, a symlink to a release directory
+// under Root/releases. Each publish builds a new release from hard links to
+// the current one, applies the collection on top, then swaps the symlink so
+// readers see either all of the collection or none of it.
+//
+// The previous KeepReleases releases are kept so readers which resolved the
+// symlink before a swap can finish reading. Only one publisher can write to
+// the directory at a time, so the publisher must hold the lease named by
+// LeaseName while publishing.
+type FilesystemTarget struct {
+	Root string
+}
+
+// KeepReleases is the number of releases kept after being replaced
+const KeepReleases = 3
+
+// NewFilesystemTarget ...
+func NewFilesystemTarget(root string) (*FilesystemTarget, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "releases"), 0755); err != nil {
+		return nil, err
+	}
+
+	return &FilesystemTarget{Root: root}, nil
+}
+
+// CurrentDir returns the directory content is currently served from
+func (t *FilesystemTarget) CurrentDir() string {
+	return filepath.Join(t.Root, "current")
+}
+
+// LeaseName returns the name of the publish target lease for the directory
+func (t *FilesystemTarget) LeaseName() string {
+	return "filesystem:" + t.Root
+}
+
+func (t *FilesystemTarget) releasesDir() string {
+	return filepath.Join(t.Root, "releases")
+}
+
+// Publish ...
+func (t *FilesystemTarget) Publish(ctx context.Context, c model.Collection, files []File, deletes []string) error {
+	release := filepath.Join(t.releasesDir(), strconv.FormatInt(time.Now().UnixNano(), 10)+"-"+c.ID)
+
+	if err := os.MkdirAll(release, 0755); err != nil {
+		return err
+	}
+
+	previous, err := filepath.EvalSymlinks(t.CurrentDir())
+	if err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(release)
+		return err
+	}
+
+	if len(previous) > 0 {
+		if err := linkTree(previous, release); err != nil {
+			os.RemoveAll(release)
+			return err
+		}
+	}
+
+	for _, uri := range deletes {
+		if err := os.RemoveAll(filepath.Join(release, filepath.FromSlash(model.CleanURI(uri)))); err != nil {
+			os.RemoveAll(release)
+			return err
+		}
+	}
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			os.RemoveAll(release)
+			return err
+		}
+
+		dst := filepath.Join(release, filepath.FromSlash(model.CleanURI(f.URI)))
+		if err := writeFile(dst, f.Path); err != nil {
+			os.RemoveAll(release)
+			return err
+		}
+	}
+
+	// the lease may have been lost while the release was built, in which
+	// case another publisher may be writing to the directory
+	if err := ctx.Err(); err != nil {
+		os.RemoveAll(release)
+		return err
+	}
+
+	tmp := t.CurrentDir() + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(release, tmp); err != nil {
+		os.RemoveAll(release)
+		return err
+	}
+
+	if err := os.Rename(tmp, t.CurrentDir()); err != nil {
+		os.Remove(tmp)
+		os.RemoveAll(release)
+		return err
+	}
+
+	// the collection is published, releases which can't be removed now are
+	// removed by a later publish
+	if err := t.prune(release); err != nil {
+		log.Error(err, log.Data{"releases": t.releasesDir()})
+	}
+
+	return nil
+}
+
+// prune removes all but the newest KeepReleases releases other than current
+func (t *FilesystemTarget) prune(current string) error {
+	entries, err := ioutil.ReadDir(t.releasesDir())
+	if err != nil {
+		return err
+	}
+
+	// release names start with the time they were created, so they sort
+	// oldest first
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && filepath.Join(t.releasesDir(), e.Name()) != current {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for len(names) > KeepReleases {
+		if err := os.RemoveAll(filepath.Join(t.releasesDir(), names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+
+	return nil
+}
+
+// linkTree recreates src under dst using hard links, falling back to copies
+func linkTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		if err := os.Link(path, target); err == nil {
+			return nil
+		}
+
+		return writeFile(target, path)
+	})
+}
+
+// writeFile copies src to dst, replacing rather than modifying any existing
+// file so hard linked copies in other releases are left untouched
+func writeFile(dst, src string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err = out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,126 @@
+package publish
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+func TestFilesystemTarget(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	target, err := NewFilesystemTarget(filepath.Join(dir, "website"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(uri string) string {
+		b, err := ioutil.ReadFile(filepath.Join(target.CurrentDir(), filepath.FromSlash(uri)))
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+
+	first := writeCollection(t, filepath.Join(dir, "first"), map[string]string{
+		"/economy/data.json":  "economy",
+		"/business/data.json": "business",
+	})
+	if err = target.Publish(context.Background(), model.Collection{ID: "first"}, first, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if read("/economy/data.json") != "economy" || read("/business/data.json") != "business" {
+		t.Fatal("first publish isn't current")
+	}
+
+	second := writeCollection(t, filepath.Join(dir, "second"), map[string]string{
+		"/economy/data.json": "economy v2",
+	})
+	if err = target.Publish(context.Background(), model.Collection{ID: "second"}, second, []string{"/business"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if read("/economy/data.json") != "economy v2" {
+		t.Error("second publish didn't replace content")
+	}
+	if read("/business/data.json") != "" {
+		t.Error("second publish didn't delete content")
+	}
+
+	releases, _ := ioutil.ReadDir(filepath.Join(dir, "website", "releases"))
+	if len(releases) != 2 {
+		t.Errorf("%d releases, want the current and previous release", len(releases))
+	}
+
+	// the previous release is unchanged for anyone still reading it
+	for _, r := range releases {
+		p := filepath.Join(dir, "website", "releases", r.Name())
+		if current, _ := filepath.EvalSymlinks(target.CurrentDir()); current == p {
+			continue
+		}
+		if b, _ := ioutil.ReadFile(filepath.Join(p, "economy", "data.json")); string(b) != "economy" {
+			t.Errorf("previous release changed: %q", b)
+		}
+	}
+}
+
+func TestFilesystemTargetPrunesReleases(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	target, err := NewFilesystemTarget(filepath.Join(dir, "website"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json": "{}",
+	})
+
+	for i := 0; i < KeepReleases+3; i++ {
+		if err = target.Publish(context.Background(), model.Collection{ID: "abc"}, files, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	releases, _ := ioutil.ReadDir(filepath.Join(dir, "website", "releases"))
+	if len(releases) != KeepReleases+1 {
+		t.Errorf("%d releases, want %d", len(releases), KeepReleases+1)
+	}
+}
+
+func TestFilesystemTargetCancelled(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	target, err := NewFilesystemTarget(filepath.Join(dir, "website"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json": "{}",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err = target.Publish(ctx, model.Collection{ID: "abc"}, files, nil); err == nil {
+		t.Error("Publish with a cancelled context succeeded")
+	}
+
+	if _, err = os.Lstat(target.CurrentDir()); !os.IsNotExist(err) {
+		t.Errorf("current release swapped after the context was cancelled: %v", err)
+	}
+
+	releases, _ := ioutil.ReadDir(filepath.Join(dir, "website", "releases"))
+	if len(releases) != 0 {
+		t.Errorf("%d releases left after a cancelled publish", len(releases))
+	}
+}
@@ -0,0 +1,90 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// HTTPTarget publishes by sending each file to an HTTP receiver.
+//
+// Files are sent with a POST to the receiver URL followed by the file's URI,
+// and deletes with a DELETE to the URI being removed. The collection ID is
+// sent in the X-Collection-Id header and the receiver key in the
+// X-Publish-Key header.
+type HTTPTarget struct {
+	URL    string
+	Key    []byte
+	Client *http.Client
+}
+
+// NewHTTPTarget ...
+func NewHTTPTarget(receiverURL string, key []byte) (*HTTPTarget, error) {
+	if len(key) == 0 {
+		return nil, ErrNoReceiverKey
+	}
+
+	return &HTTPTarget{
+		URL:    strings.TrimSuffix(receiverURL, "/"),
+		Key:    key,
+		Client: &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+// Publish ...
+func (t *HTTPTarget) Publish(ctx context.Context, c model.Collection, files []File, deletes []string) error {
+	for _, uri := range deletes {
+		if err := t.send(ctx, c, "DELETE", uri, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		if err := t.sendFile(ctx, c, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *HTTPTarget) sendFile(ctx context.Context, c model.Collection, f File) error {
+	in, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return t.send(ctx, c, "POST", f.URI, in)
+}
+
+func (t *HTTPTarget) send(ctx context.Context, c model.Collection, method, uri string, body io.Reader) error {
+	u := t.URL + (&url.URL{Path: model.CleanURI(uri)}).EscapedPath()
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Collection-Id", c.ID)
+	req.Header.Set(ReceiverKeyHeader, string(t.Key))
+
+	res, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("publish receiver returned %d for %s %s", res.StatusCode, method, uri)
+	}
+
+	return nil
+}
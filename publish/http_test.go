@@ -0,0 +1,93 @@
+package publish
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+func TestHTTPTarget(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	received := filepath.Join(dir, "received")
+	srv := httptest.NewServer(&Receiver{Dir: received, Key: []byte("secret")})
+	defer srv.Close()
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json":          `{"type":"taxonomy_landing_page"}`,
+		"/economy/inflation/cpi.xlsx": "cpi",
+		"/economy/a page/data.json":   "{}",
+	})
+
+	if err := os.MkdirAll(filepath.Join(received, "business"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := NewHTTPTarget(srv.URL+"/", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := model.Collection{ID: "abc"}
+	if err = target.Publish(context.Background(), c, files, []string{"/business"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range files {
+		want, _ := ioutil.ReadFile(f.Path)
+		got, err := ioutil.ReadFile(filepath.Join(received, filepath.FromSlash(f.URI)))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("received %s = %q, %v, want %q", f.URI, got, err, want)
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(received, "business")); !os.IsNotExist(err) {
+		t.Errorf("deleted content still exists: %v", err)
+	}
+}
+
+func TestHTTPTargetErrors(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if _, err := NewHTTPTarget("http://localhost", nil); err != ErrNoReceiverKey {
+		t.Errorf("NewHTTPTarget without a key = %v, want ErrNoReceiverKey", err)
+	}
+
+	received := filepath.Join(dir, "received")
+	srv := httptest.NewServer(&Receiver{Dir: received, Key: []byte("secret")})
+	defer srv.Close()
+
+	files := writeCollection(t, filepath.Join(dir, "collection"), map[string]string{
+		"/economy/data.json": "{}",
+	})
+	c := model.Collection{ID: "abc"}
+
+	target, err := NewHTTPTarget(srv.URL, []byte("wrong"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = target.Publish(context.Background(), c, files, nil); err == nil {
+		t.Error("Publish with the wrong key succeeded")
+	}
+
+	target.Key = []byte("secret")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err = target.Publish(ctx, c, files, nil); err == nil {
+		t.Error("Publish with a cancelled context succeeded")
+	}
+
+	if _, err = os.Stat(received); !os.IsNotExist(err) {
+		t.Errorf("content received after the context was cancelled: %v", err)
+	}
+}
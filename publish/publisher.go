@@ -0,0 +1,273 @@
+package publish
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// DefaultLeaseTTL is how long a publisher holds a collection before another
+// publisher may assume it has died and take over
+const DefaultLeaseTTL = time.Minute * 10
+
+// Step is run against a collection as part of the publish pipeline. The
+// context is cancelled if the publish lease is lost while the step runs.
+type Step func(ctx context.Context, c model.Collection, r *Result) error
+
+// Result is filled in by the steps of the publish pipeline
+type Result struct {
+	// Files is the number of files published
+	Files int
+}
+
+// Publisher runs collections through the publish pipeline
+type Publisher struct {
+	DB       *data.MongoDB
+	Owner    string
+	LeaseTTL time.Duration
+	Checks   []Check
+	Steps    []Step
+
+	// TargetLease names a lease held while the steps run, for targets which
+	// only one publisher can write to at a time. It's empty if collections
+	// can be published concurrently.
+	TargetLease string
+}
+
+type publishCompleteDetail struct {
+	PublishRecordID string `bson:"publish_record_id"`
+}
+
+type publishFailedDetail struct {
+	Error    string         `bson:"error"`
+	Failures []CheckFailure `bson:"failures,omitempty"`
+}
+
+// New creates a Publisher with an owner ID unique to this process
+func New(db *data.MongoDB, steps ...Step) (*Publisher, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := data.GenerateRandomString(8)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher{
+		DB:       db,
+		Owner:    host + "/" + id,
+		LeaseTTL: DefaultLeaseTTL,
+		Checks:   []Check{ContentReviewedCheck(db)},
+		Steps:    steps,
+	}, nil
+}
+
+// Check runs the pre-publish checks against a collection
+func (p *Publisher) Check(c model.Collection) error {
+	return RunChecks(c, p.Checks...)
+}
+
+// Publish runs the pre-publish checks against a collection, takes its
+// publish lease and runs each step of the pipeline, then marks the
+// collection as published and records it in the publish history. The
+// release the collection is linked to, if any, is marked as published.
+//
+// A CheckError is returned without changing the collection if the checks
+// fail. If the publish fails after the lease is taken the collection is
+// moved to the approval error state.
+func (p *Publisher) Publish(id, email string) error {
+	c, err := p.DB.GetCollection(id)
+	if err != nil {
+		return err
+	}
+
+	if err = p.Check(c); err != nil {
+		return err
+	}
+
+	if len(p.TargetLease) > 0 {
+		if err = p.DB.AcquireTargetLease(p.TargetLease, p.Owner, p.LeaseTTL); err != nil {
+			return err
+		}
+		defer func() {
+			if err := p.DB.ReleaseTargetLease(p.TargetLease, p.Owner); err != nil {
+				log.Error(err, log.Data{"target_lease": p.TargetLease})
+			}
+		}()
+	}
+
+	err = p.DB.AcquirePublishLease(id, p.Owner, p.LeaseTTL)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+
+	// the collection may have changed before the lease was taken
+	c, err = p.DB.GetCollection(id)
+	if err != nil {
+		return p.fail(id, email, err)
+	}
+
+	err = p.DB.CreateCollectionEvent("PUBLISH_STARTED", id, email)
+	if err != nil {
+		return p.fail(id, email, err)
+	}
+
+	var result Result
+	for _, step := range p.Steps {
+		if err = p.runStep(step, c, &result); err != nil {
+			return p.fail(id, email, err)
+		}
+	}
+
+	err = p.DB.CompletePublish(id, p.Owner)
+	if err != nil {
+		return p.fail(id, email, err)
+	}
+
+	// the collection is published, errors from here on are logged rather
+	// than returned so the rest of the bookkeeping still happens
+	if err = p.DB.ReleaseContentLocks(id); err != nil {
+		log.Error(err, log.Data{"collection_id": id})
+	}
+
+	recordID, err := p.DB.CreatePublishRecord(model.PublishRecord{
+		CollectionID:   c.ID,
+		CollectionName: c.Name,
+		CollectionType: c.Type,
+		PublishedBy:    email,
+		Started:        started,
+		Completed:      time.Now(),
+		Files:          result.Files,
+		PendingDeletes: len(c.PendingDeletes),
+		Teams:          c.Teams,
+	})
+	if err != nil {
+		log.Error(err, log.Data{"collection_id": id})
+	}
+
+	err = p.DB.CreateCollectionEventWithDetail("PUBLISH_COMPLETE", id, email, publishCompleteDetail{recordID})
+	if err != nil {
+		log.Error(err, log.Data{"collection_id": id})
+	}
+
+	if len(c.ReleaseURI) > 0 {
+		if err = p.DB.PublishRelease(c.ReleaseURI); err != nil {
+			log.Error(err, log.Data{"collection_id": id, "release_uri": c.ReleaseURI})
+		}
+	}
+
+	return nil
+}
+
+// Reject moves a collection which failed its pre-publish checks to the
+// approval error state, so the scheduler doesn't keep retrying it
+func (p *Publisher) Reject(id, email string, err error) error {
+	if err2 := p.DB.AcquirePublishLease(id, p.Owner, p.LeaseTTL); err2 != nil {
+		return err2
+	}
+
+	return p.fail(id, email, err)
+}
+
+// runStep runs a step while renewing the leases in the background,
+// cancelling the step if they can't be renewed
+func (p *Publisher) runStep(step Step, c model.Collection, r *Result) error {
+	renew := func() error {
+		return p.renew(c.ID)
+	}
+
+	if err := renew(); err != nil {
+		return err
+	}
+
+	return heartbeat(p.LeaseTTL/3, renew, func(ctx context.Context) error {
+		return step(ctx, c, r)
+	})
+}
+
+// heartbeat runs fn, calling renew every interval until it returns. If renew
+// fails the context passed to fn is cancelled and the error from renew is
+// returned.
+func heartbeat(interval time.Duration, renew func() error, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		lost error
+		done = make(chan struct{})
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				if err := renew(); err != nil {
+					lost = err
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := fn(ctx)
+	close(done)
+	wg.Wait()
+
+	if lost != nil {
+		return lost
+	}
+	return err
+}
+
+// renew extends the publish lease on a collection and the target lease
+func (p *Publisher) renew(id string) error {
+	if err := p.DB.RenewPublishLease(id, p.Owner, p.LeaseTTL); err != nil {
+		return err
+	}
+
+	if len(p.TargetLease) > 0 {
+		return p.DB.RenewTargetLease(p.TargetLease, p.Owner, p.LeaseTTL)
+	}
+
+	return nil
+}
+
+// fail records a failed publish, moving the collection to the approval error
+// state so the scheduler doesn't keep retrying it
+func (p *Publisher) fail(id, email string, err error) error {
+	log.Error(err, log.Data{"collection_id": id})
+
+	if err2 := p.DB.FailPublish(id, p.Owner); err2 != nil {
+		log.Error(err2, log.Data{"collection_id": id})
+	}
+
+	detail := publishFailedDetail{Error: err.Error()}
+	if cErr, ok := err.(*CheckError); ok {
+		detail.Failures = cErr.Failures
+	}
+
+	if err2 := p.DB.CreateCollectionEventWithDetail("PUBLISH_FAILED", id, email, detail); err2 != nil {
+		log.Error(err2, log.Data{"collection_id": id})
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var renewals int32
+	renew := func() error {
+		atomic.AddInt32(&renewals, 1)
+		return nil
+	}
+
+	err := heartbeat(time.Millisecond, renew, func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 20)
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Errorf("heartbeat = %v", err)
+	}
+
+	if atomic.LoadInt32(&renewals) == 0 {
+		t.Error("lease wasn't renewed while the step ran")
+	}
+
+	stepErr := errors.New("step failed")
+	err = heartbeat(time.Hour, renew, func(ctx context.Context) error {
+		return stepErr
+	})
+	if err != stepErr {
+		t.Errorf("heartbeat = %v, want the step's error", err)
+	}
+}
+
+func TestHeartbeatLeaseLost(t *testing.T) {
+	lost := errors.New("lease lost")
+
+	var cancelled bool
+	err := heartbeat(time.Millisecond, func() error {
+		return lost
+	}, func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			cancelled = true
+			return ctx.Err()
+		case <-time.After(time.Second * 5):
+			return nil
+		}
+	})
+
+	if err != lost {
+		t.Errorf("heartbeat = %v, want the renewal error", err)
+	}
+
+	if !cancelled {
+		t.Error("step wasn't cancelled when the lease was lost")
+	}
+}
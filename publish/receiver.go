@@ -0,0 +1,82 @@
+package publish
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// ErrNoReceiverKey ...
+var ErrNoReceiverKey = errors.New("publish receiver key not configured")
+
+// ReceiverKeyHeader carries the key shared between an HTTPTarget and its
+// Receiver
+const ReceiverKeyHeader = "X-Publish-Key"
+
+// Receiver is an http.Handler which accepts files sent by an HTTPTarget and
+// writes them under Dir, for local development and testing. Requests must
+// carry Key in the X-Publish-Key header.
+type Receiver struct {
+	Dir string
+	Key []byte
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key := []byte(req.Header.Get(ReceiverKeyHeader))
+	if len(r.Key) == 0 || subtle.ConstantTimeCompare(key, r.Key) != 1 {
+		log.DebugR(req, "invalid publish receiver key", nil)
+		w.WriteHeader(401)
+		return
+	}
+
+	uri := model.CleanURI(req.URL.Path)
+	if uri == "/" {
+		log.DebugR(req, "publish receiver can't change the root", nil)
+		w.WriteHeader(400)
+		return
+	}
+
+	path := filepath.Join(r.Dir, filepath.FromSlash(uri))
+
+	log.DebugR(req, "publish receiver", log.Data{"method": req.Method, "path": path, "collection_id": req.Header.Get("X-Collection-Id")})
+
+	switch req.Method {
+	case "POST":
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+
+		f, err := os.Create(path)
+		if err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+		defer f.Close()
+
+		if _, err = io.Copy(f, req.Body); err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+	case "DELETE":
+		if err := os.RemoveAll(path); err != nil {
+			log.ErrorR(req, err, nil)
+			w.WriteHeader(500)
+			return
+		}
+	default:
+		w.WriteHeader(405)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,84 @@
+package publish
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReceiver(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	r := &Receiver{Dir: dir, Key: []byte("secret")}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"no key", "POST", "/economy/data.json", "", 401},
+		{"wrong key", "POST", "/economy/data.json", "wrong", 401},
+		{"write", "POST", "/economy/data.json", "secret", 204},
+		{"write outside dir", "POST", "/../../outside.json", "secret", 204},
+		{"root", "DELETE", "/", "secret", 400},
+		{"root after clean", "DELETE", "/economy/..", "secret", 400},
+		{"method", "PUT", "/economy/data.json", "secret", 405},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "http://receiver"+tt.path, strings.NewReader("{}"))
+		if len(tt.key) > 0 {
+			req.Header.Set(ReceiverKeyHeader, tt.key)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "economy", "data.json")); err != nil || string(b) != "{}" {
+		t.Errorf("written file = %q, %v", b, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "outside.json")); err != nil {
+		t.Errorf("file written outside the receiver directory: %v", err)
+	}
+
+	req := httptest.NewRequest("DELETE", "http://receiver/economy", nil)
+	req.Header.Set(ReceiverKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("delete: status = %d, want 204", w.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "economy")); !os.IsNotExist(err) {
+		t.Errorf("deleted content still exists: %v", err)
+	}
+}
+
+func TestReceiverWithoutKey(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	r := &Receiver{Dir: dir}
+
+	req := httptest.NewRequest("POST", "http://receiver/economy/data.json", strings.NewReader("{}"))
+	req.Header.Set(ReceiverKeyHeader, "")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package publish
+
+import (
+	"time"
+
+	"github.com/ONSdigital/dp-florence-api/data"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// DefaultSchedulerInterval ...
+const DefaultSchedulerInterval = time.Second * 10
+
+// Scheduler publishes scheduled collections once their publish date passes
+type Scheduler struct {
+	publisher *Publisher
+	interval  time.Duration
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewScheduler ...
+func NewScheduler(p *Publisher, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		publisher: p,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.done)
+
+		t := time.NewTicker(s.interval)
+		defer t.Stop()
+
+		for {
+			s.run(time.Now())
+
+			select {
+			case <-t.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop waits for any publish in progress to finish and stops the scheduler
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *Scheduler) run(now time.Time) {
+	cols, err := s.publisher.DB.ListDueCollections(now)
+	if err != nil {
+		log.Error(err, nil)
+		return
+	}
+
+	for _, c := range cols {
+		if c.Publishing() {
+			continue
+		}
+
+		log.Debug("publishing scheduled collection", log.Data{"collection_id": c.ID, "publish_date": c.PublishDate})
+
+		err = s.publisher.Publish(c.ID, data.AuditSystemUser)
+		if err == data.ErrPublishLeaseHeld {
+			log.Debug("collection is already being published", log.Data{"collection_id": c.ID})
+			continue
+		}
+		if err == data.ErrTargetLeaseHeld {
+			log.Debug("publish target is busy", log.Data{"collection_id": c.ID})
+			return
+		}
+		if cErr, ok := err.(*CheckError); ok {
+			// nobody is waiting on a scheduled publish to see the failures, so
+			// the collection is moved to the approval error state instead
+			err = s.publisher.Reject(c.ID, data.AuditSystemUser, cErr)
+		}
+		if err != nil {
+			log.Error(err, log.Data{"collection_id": c.ID})
+		}
+	}
+}
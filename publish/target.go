@@ -0,0 +1,72 @@
+package publish
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ONSdigital/dp-florence-api/data/model"
+)
+
+// ErrUnknownTarget ...
+var ErrUnknownTarget = errors.New("unknown publish target")
+
+// PublishTarget is a destination collections are published to
+type PublishTarget interface {
+	// Publish writes files to the target and removes the content under
+	// each of the deletes URIs. It stops without completing the publish if
+	// ctx is cancelled.
+	Publish(ctx context.Context, c model.Collection, files []File, deletes []string) error
+}
+
+// Config selects and configures a publish target.
+//
+// Only the filesystem target updates content this service can read. The
+// http and bundle targets hand content to another system, so the content
+// directory has to be pointed at wherever that system publishes to.
+type Config struct {
+	// Target is one of "filesystem", "http" or "bundle"
+	Target string
+
+	WebsiteContentDir string
+	ReceiverURL       string
+	ReceiverKey       []byte
+	BundleDir         string
+	BundleKey         []byte
+}
+
+// NewTarget creates the publish target named in cfg
+func NewTarget(cfg Config) (PublishTarget, error) {
+	switch cfg.Target {
+	case "filesystem":
+		return NewFilesystemTarget(cfg.WebsiteContentDir)
+	case "http":
+		return NewHTTPTarget(cfg.ReceiverURL, cfg.ReceiverKey)
+	case "bundle":
+		return NewBundleTarget(cfg.BundleDir, cfg.BundleKey)
+	}
+
+	return nil, ErrUnknownTarget
+}
+
+// TargetStep publishes the collection's working directory to a target,
+// deleting the content under each of the collection's pending deletes
+func TargetStep(collectionsDir string, t PublishTarget) Step {
+	return func(ctx context.Context, c model.Collection, r *Result) error {
+		files, err := CollectionFiles(collectionsDir, c.ID)
+		if err != nil {
+			return err
+		}
+
+		var deletes []string
+		for _, d := range c.PendingDeletes {
+			deletes = append(deletes, d.URI)
+		}
+
+		if err = t.Publish(ctx, c, files, deletes); err != nil {
+			return err
+		}
+
+		r.Files += len(files)
+		return nil
+	}
+}